@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Syslog priorities used for --check results.
+const (
+	prioErr     = 3
+	prioWarning = 4
+	prioInfo    = 6
+)
+
+// alertLogger forwards --check findings to a system log.
+type alertLogger interface {
+	Log(priority int, msg string) error
+	Close() error
+}
+
+// openAlertLog opens "syslog" or "journal"; "" returns a nil logger.
+func openAlertLog(target string) (alertLogger, error) {
+	switch target {
+	case "":
+		return nil, nil
+	case "syslog":
+		return openSyslog()
+	case "journal":
+		return openJournal()
+	}
+	return nil, fmt.Errorf("log: unknown target %q (use syslog or journal)", target)
+}
+
+// journalLogger speaks the systemd-journald native protocol over its datagram socket.
+type journalLogger struct {
+	conn net.Conn
+}
+
+func openJournal() (alertLogger, error) {
+	conn, err := net.Dial("unixgram", "/run/systemd/journal/socket")
+	if err != nil {
+		return nil, fmt.Errorf("log: journal: %w", err)
+	}
+	return &journalLogger{conn: conn}, nil
+}
+
+func (j *journalLogger) Log(priority int, msg string) error {
+	var b strings.Builder
+	field := func(key, value string) {
+		if strings.Contains(value, "\n") { // Multi-line values use the binary length form
+			b.WriteString(key + "\n")
+			n := uint64(len(value))
+			for i := 0; i < 8; i++ {
+				b.WriteByte(byte(n >> (8 * i)))
+			}
+			b.WriteString(value + "\n")
+			return
+		}
+		b.WriteString(key + "=" + value + "\n")
+	}
+	field("MESSAGE", msg)
+	field("PRIORITY", strconv.Itoa(priority))
+	field("SYSLOG_IDENTIFIER", "kernelview")
+	field("SYSLOG_PID", strconv.Itoa(os.Getpid()))
+	_, err := j.conn.Write([]byte(b.String()))
+	return err
+}
+
+func (j *journalLogger) Close() error {
+	return j.conn.Close()
+}
@@ -0,0 +1,9 @@
+//go:build windows || plan9
+
+package main
+
+import "errors"
+
+func openSyslog() (alertLogger, error) {
+	return nil, errors.New("log: syslog is not available on this platform")
+}
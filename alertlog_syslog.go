@@ -0,0 +1,34 @@
+//go:build !windows && !plan9
+
+package main
+
+import (
+	"fmt"
+	"log/syslog"
+)
+
+type syslogLogger struct {
+	w *syslog.Writer
+}
+
+func openSyslog() (alertLogger, error) {
+	w, err := syslog.New(syslog.LOG_DAEMON|syslog.LOG_INFO, "kernelview")
+	if err != nil {
+		return nil, fmt.Errorf("log: syslog: %w", err)
+	}
+	return &syslogLogger{w: w}, nil
+}
+
+func (s *syslogLogger) Log(priority int, msg string) error {
+	switch priority {
+	case prioErr:
+		return s.w.Err(msg)
+	case prioWarning:
+		return s.w.Warning(msg)
+	}
+	return s.w.Info(msg)
+}
+
+func (s *syslogLogger) Close() error {
+	return s.w.Close()
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"KernelView-Go/gather"
+)
+
+// Exit codes for --check, following the Nagios plugin convention.
+const (
+	checkOK      = 0
+	checkFailed  = 1
+	checkUnknown = 3
+)
+
+var checkSpecRe = regexp.MustCompile(`^\s*([a-z]+)\s*(>=|<=|>|<|=)\s*([0-9.]+)\s*%?\s*$`)
+
+// checkMetrics reads each metric from the gathered numbers.
+var checkMetrics = map[string]func(m *gather.Metrics) (float64, bool){
+	"disk": func(m *gather.Metrics) (float64, bool) { return usagePercent(m.Disk) },
+	"ram":  func(m *gather.Metrics) (float64, bool) { return usagePercent(m.RAM) },
+	"swap": func(m *gather.Metrics) (float64, bool) { return usagePercent(m.Swap) },
+	"cpu":  func(m *gather.Metrics) (float64, bool) { return reading(m.CPUUsage) },
+	"temp": func(m *gather.Metrics) (float64, bool) {
+		// The hotter of the CPU and SoC sensors
+		cpu, okCPU := reading(m.Temperature)
+		soc, okSoC := reading(m.SoCTemp)
+		switch {
+		case okCPU && okSoC:
+			return max(cpu, soc), true
+		case okSoC:
+			return soc, true
+		}
+		return cpu, okCPU
+	},
+	"units": func(m *gather.Metrics) (float64, bool) {
+		if m.FailedUnits == nil {
+			return 0, false
+		}
+		return float64(*m.FailedUnits), true
+	},
+}
+
+// checkUnits follow each reading in the FAIL lines; metrics without one are
+// counts, printed without decimals.
+var checkUnits = map[string]string{"disk": "%", "ram": "%", "swap": "%", "cpu": "%", "temp": " °C", "units": ""}
+
+type threshold struct {
+	metric string
+	op     string
+	limit  float64
+}
+
+func usagePercent(u *gather.Usage) (float64, bool) {
+	if u == nil {
+		return 0, false
+	}
+	return u.Percent, true
+}
+
+func reading(v *float64) (float64, bool) {
+	if v == nil {
+		return 0, false
+	}
+	return *v, true
+}
+
+// parseChecks parses a spec such as "disk>90,ram>95,temp>=80".
+func parseChecks(spec string) ([]threshold, error) {
+	var checks []threshold
+	for _, part := range strings.Split(spec, ",") {
+		m := checkSpecRe.FindStringSubmatch(strings.ToLower(part))
+		if m == nil {
+			return nil, fmt.Errorf("check: invalid threshold %q (want e.g. disk>90)", part)
+		}
+		if checkMetrics[m[1]] == nil {
+			var names []string
+			for name := range checkMetrics {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return nil, fmt.Errorf("check: unknown metric %q (use %s)", m[1], strings.Join(names, ", "))
+		}
+		limit, err := strconv.ParseFloat(m[3], 64)
+		if err != nil {
+			return nil, fmt.Errorf("check: invalid threshold %q: %w", part, err)
+		}
+		checks = append(checks, threshold{metric: m[1], op: m[2], limit: limit})
+	}
+	return checks, nil
+}
+
+func (t threshold) violatedBy(v float64) bool {
+	switch t.op {
+	case ">":
+		return v > t.limit
+	case ">=":
+		return v >= t.limit
+	case "<":
+		return v < t.limit
+	case "<=":
+		return v <= t.limit
+	}
+	return v == t.limit
+}
+
+// runChecks prints one line per violated or unreadable threshold, or a single
+// OK line, mirroring each to logger (if any) with a matching priority. It
+// returns the exit code and the problem lines.
+func runChecks(checks []threshold, info *gather.SystemInfo, logger alertLogger) (int, []string) {
+	var problems []string
+	report := func(priority int, msg string) {
+		fmt.Println(msg)
+		if priority != prioInfo {
+			problems = append(problems, msg)
+		}
+		if logger != nil {
+			if err := logger.Log(priority, msg); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			}
+		}
+	}
+	code := checkOK
+	for _, t := range checks {
+		v, ok := checkMetrics[t.metric](&info.Metrics)
+		if !ok {
+			report(prioWarning, fmt.Sprintf("UNKNOWN: %s could not be read", t.metric))
+			if code == checkOK {
+				code = checkUnknown
+			}
+			continue
+		}
+		if t.violatedBy(v) {
+			decimals := 1
+			if checkUnits[t.metric] == "" {
+				decimals = 0
+			}
+			report(prioErr, fmt.Sprintf("FAIL: %s is %.*f%s (threshold %s%g)", t.metric, decimals, v, checkUnits[t.metric], t.op, t.limit))
+			code = checkFailed
+		}
+	}
+	if code == checkOK {
+		report(prioInfo, "OK: all thresholds passed")
+	}
+	return code, problems
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseChecks(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []threshold
+		wantErr bool
+	}{
+		{"disk>90", []threshold{{"disk", ">", 90}}, false},
+		{"disk>90, RAM >= 95%,temp<=80.5", []threshold{{"disk", ">", 90}, {"ram", ">=", 95}, {"temp", "<=", 80.5}}, false},
+		{"swap=0", []threshold{{"swap", "=", 0}}, false},
+		{"units>0", []threshold{{"units", ">", 0}}, false},
+		{"gpu>50", nil, true},
+		{"disk", nil, true},
+		{"disk>>90", nil, true},
+		{"", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := parseChecks(tt.spec)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseChecks(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseChecks(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestViolatedBy(t *testing.T) {
+	tests := []struct {
+		op   string
+		v    float64
+		want bool
+	}{
+		{">", 91, true}, {">", 90, false},
+		{">=", 90, true}, {">=", 89.9, false},
+		{"<", 89, true}, {"<", 90, false},
+		{"<=", 90, true}, {"<=", 90.1, false},
+		{"=", 90, true}, {"=", 91, false},
+	}
+	for _, tt := range tests {
+		if got := (threshold{metric: "disk", op: tt.op, limit: 90}).violatedBy(tt.v); got != tt.want {
+			t.Errorf("%g %s 90 = %v, want %v", tt.v, tt.op, got, tt.want)
+		}
+	}
+}
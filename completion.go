@@ -0,0 +1,187 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"KernelView-Go/display"
+)
+
+// Value completions for flags that take an argument; flags not listed here
+// take free text (e.g. --format).
+var flagValues = map[string][]string{
+	"output": {"text", "html", "html-fragment", "csv", "tsv"},
+	"logo":   {"distro", "none"},
+	"lang":   display.Languages(),
+	"icons":  {"nerd", "emoji", "none"},
+	"style":  display.LayoutStyles(),
+	"theme":  display.ThemeNames(),
+	"accent": {"random", "distro"},
+	"ports":  {"all"},
+	"log":    {"syslog", "journal"},
+}
+
+// Flags whose argument is (or may be) a file path.
+var fileFlags = map[string]bool{"config": true, "export": true, "logo": true, "snapshot": true, "diff": true, "history": true}
+
+type completionFlag struct {
+	name    string
+	desc    string
+	isBool  bool
+	values  []string
+	isFile  bool
+	display string // "--name" or "-n"
+}
+
+// completionFlags describes every registered flag, so scripts never drift from main.
+func completionFlags() []completionFlag {
+	var flags []completionFlag
+	flag.VisitAll(func(f *flag.Flag) {
+		b, ok := f.Value.(interface{ IsBoolFlag() bool })
+		cf := completionFlag{
+			name:    f.Name,
+			desc:    shortUsage(f.Usage),
+			isBool:  ok && b.IsBoolFlag(),
+			values:  flagValues[f.Name],
+			isFile:  fileFlags[f.Name],
+			display: "--" + f.Name,
+		}
+		if len(f.Name) == 1 {
+			cf.display = "-" + f.Name
+		}
+		flags = append(flags, cf)
+	})
+	sort.Slice(flags, func(i, j int) bool { return flags[i].name < flags[j].name })
+	return flags
+}
+
+// shortUsage trims a flag's usage text to its leading phrase for completion menus.
+func shortUsage(usage string) string {
+	end := len(usage)
+	for _, stop := range []string{". ", ": ", " (", ", e.g."} {
+		if i := strings.Index(usage, stop); i > 0 && i < end {
+			end = i
+		}
+	}
+	return strings.TrimSuffix(usage[:end], ".")
+}
+
+// writeCompletion prints the completion script for shell.
+func writeCompletion(w io.Writer, shell string) error {
+	flags := completionFlags()
+	switch shell {
+	case "bash":
+		writeBashCompletion(w, flags)
+	case "zsh":
+		writeZshCompletion(w, flags)
+	case "fish":
+		writeFishCompletion(w, flags)
+	case "powershell":
+		writePowerShellCompletion(w, flags)
+	default:
+		return fmt.Errorf("completion: unsupported shell %q (use bash, zsh, fish or powershell)", shell)
+	}
+	return nil
+}
+
+func writeBashCompletion(w io.Writer, flags []completionFlag) {
+	var all []string
+	fmt.Fprint(w, "# bash completion for kernelview; load with: source <(kernelview completion bash)\n")
+	fmt.Fprint(w, "_kernelview() {\n    local cur=\"${COMP_WORDS[COMP_CWORD]}\" prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n    case \"$prev\" in\n")
+	for _, f := range flags {
+		all = append(all, f.display)
+		if f.isBool {
+			continue
+		}
+		var reply []string
+		if len(f.values) > 0 {
+			reply = append(reply, fmt.Sprintf("$(compgen -W %q -- \"$cur\")", strings.Join(f.values, " ")))
+		}
+		if f.isFile {
+			reply = append(reply, "$(compgen -f -- \"$cur\")")
+		}
+		fmt.Fprintf(w, "        %s|-%s)\n            COMPREPLY=(%s)\n            return ;;\n", f.display, f.name, strings.Join(reply, " "))
+	}
+	fmt.Fprint(w, "    esac\n")
+	fmt.Fprint(w, "    if [[ $COMP_CWORD -eq 1 && \"$cur\" != -* ]]; then\n        COMPREPLY=($(compgen -W \"completion\" -- \"$cur\"))\n        return\n    fi\n")
+	fmt.Fprint(w, "    if [[ \"${COMP_WORDS[1]}\" == completion ]]; then\n        COMPREPLY=($(compgen -W \"bash zsh fish powershell\" -- \"$cur\"))\n        return\n    fi\n")
+	fmt.Fprintf(w, "    COMPREPLY=($(compgen -W %q -- \"$cur\"))\n}\ncomplete -o filenames -F _kernelview kernelview\n", strings.Join(all, " "))
+}
+
+func writeZshCompletion(w io.Writer, flags []completionFlag) {
+	fmt.Fprint(w, "#compdef kernelview\n# zsh completion for kernelview; save as _kernelview somewhere in $fpath\n\n")
+	fmt.Fprint(w, "_kernelview() {\n    if (( CURRENT == 3 )) && [[ $words[2] == completion ]]; then\n        _values shell bash zsh fish powershell\n        return\n    fi\n")
+	fmt.Fprint(w, "    _arguments -s \\\n        '1::command:(completion)' \\\n")
+	escape := strings.NewReplacer("'", "'\\''", "[", "\\[", "]", "\\]", ":", "\\:")
+	for _, f := range flags {
+		spec := fmt.Sprintf("%s[%s]", f.display, escape.Replace(f.desc))
+		switch {
+		case f.isBool:
+		case len(f.values) > 0 && f.isFile:
+			spec += fmt.Sprintf(":%s:{_alternative \"values:value:(%s)\" \"files:file:_files\"}", f.name, strings.Join(f.values, " "))
+		case len(f.values) > 0:
+			spec += fmt.Sprintf(":%s:(%s)", f.name, strings.Join(f.values, " "))
+		case f.isFile:
+			spec += fmt.Sprintf(":%s:_files", f.name)
+		default:
+			spec += fmt.Sprintf(":%s: ", f.name)
+		}
+		fmt.Fprintf(w, "        '%s' \\\n", spec)
+	}
+	fmt.Fprint(w, "        && return 0\n}\n\n_kernelview \"$@\"\n")
+}
+
+func writeFishCompletion(w io.Writer, flags []completionFlag) {
+	fmt.Fprint(w, "# fish completion for kernelview; load with: kernelview completion fish | source\n")
+	fmt.Fprint(w, "complete -c kernelview -n '__fish_use_subcommand' -a completion -d 'Print a shell completion script'\n")
+	fmt.Fprint(w, "complete -c kernelview -n '__fish_seen_subcommand_from completion' -x -a 'bash zsh fish powershell'\n")
+	quote := strings.NewReplacer(`\`, `\\`, "'", `\'`)
+	for _, f := range flags {
+		option := "-l " + f.name
+		if len(f.name) == 1 {
+			option = "-s " + f.name
+		}
+		line := fmt.Sprintf("complete -c kernelview %s -d '%s'", option, quote.Replace(f.desc))
+		switch {
+		case f.isBool:
+		case len(f.values) > 0 && f.isFile:
+			line += fmt.Sprintf(" -r -F -a '%s'", strings.Join(f.values, " "))
+		case len(f.values) > 0:
+			line += fmt.Sprintf(" -x -a '%s'", strings.Join(f.values, " "))
+		case f.isFile:
+			line += " -r -F"
+		default:
+			line += " -x"
+		}
+		fmt.Fprintln(w, line)
+	}
+}
+
+func writePowerShellCompletion(w io.Writer, flags []completionFlag) {
+	fmt.Fprint(w, "# PowerShell completion for kernelview; add to $PROFILE: kernelview completion powershell | Out-String | Invoke-Expression\n")
+	fmt.Fprint(w, "Register-ArgumentCompleter -Native -CommandName kernelview, kernelview.exe -ScriptBlock {\n")
+	fmt.Fprint(w, "    param($wordToComplete, $commandAst, $cursorPosition)\n")
+	fmt.Fprint(w, "    $words = @($commandAst.CommandElements | ForEach-Object { $_.ToString() })\n")
+	fmt.Fprint(w, "    $prev = if ($wordToComplete) { $words[-2] } else { $words[-1] }\n")
+	fmt.Fprint(w, "    $values = switch ($prev) {\n")
+	fmt.Fprint(w, "        'completion' { @('bash', 'zsh', 'fish', 'powershell') }\n")
+	for _, f := range flags {
+		if len(f.values) > 0 {
+			fmt.Fprintf(w, "        { $_ -in '%s', '-%s' } { @('%s') }\n", f.display, f.name, strings.Join(f.values, "', '"))
+		}
+	}
+	fmt.Fprint(w, "    }\n")
+	fmt.Fprint(w, "    if ($values) {\n        $values | Where-Object { $_ -like \"$wordToComplete*\" } | ForEach-Object {\n")
+	fmt.Fprint(w, "            [System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)\n        }\n        return\n    }\n")
+	fmt.Fprint(w, "    $flags = @(\n")
+	quote := strings.NewReplacer("'", "''")
+	for _, f := range flags {
+		fmt.Fprintf(w, "        @('%s', '%s')\n", f.display, quote.Replace(f.desc))
+	}
+	fmt.Fprint(w, "    )\n")
+	fmt.Fprint(w, "    $flags | Where-Object { $_[0] -like \"$wordToComplete*\" } | ForEach-Object {\n")
+	fmt.Fprint(w, "        [System.Management.Automation.CompletionResult]::new($_[0], $_[0], 'ParameterName', $_[1])\n    }\n}\n")
+}
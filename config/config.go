@@ -0,0 +1,133 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"KernelView-Go/display"
+	"KernelView-Go/gather"
+)
+
+// Config mirrors the JSON config file (exported).
+type Config struct {
+	Commands   gather.CommandPolicy   `json:"commands"`
+	Logo       display.LogoOptions    `json:"logo"`
+	Style      string                 `json:"style"` // Layout style; see display.SetStyle
+	Title      display.TitleOptions   `json:"title"`
+	Theme      string                 `json:"theme"`  // Built-in theme; see display.ThemeNames
+	Colors     display.ThemeColors    `json:"colors"` // Hex theme colours; see display.HexColor
+	Icons      display.IconOptions    `json:"icons"`
+	Groups     []display.GroupOptions `json:"groups"` // Group order and field placement; see display.SetGroups
+	OneLine    display.OneLineOptions `json:"oneline"`
+	Thresholds display.Thresholds     `json:"thresholds"` // Value colours; unset metrics keep display.DefaultThresholds
+	Cache      CacheConfig            `json:"cache"`
+	History    string                 `json:"history"` // Append every run to this JSON lines file; "" disables
+	Webhook    WebhookConfig          `json:"webhook"`
+}
+
+// WebhookConfig is where --check posts its failures.
+type WebhookConfig struct {
+	URL         string `json:"url"`
+	Format      string `json:"format"`       // "slack", "discord" or "generic" (default)
+	MinInterval string `json:"min_interval"` // Go duration between posts; default 1h
+}
+
+// DefaultWebhookInterval applies when webhook.min_interval is unset.
+const DefaultWebhookInterval = time.Hour
+
+// Interval returns the configured minimum time between posts.
+func (w WebhookConfig) Interval() time.Duration {
+	if w.MinInterval == "" {
+		return DefaultWebhookInterval
+	}
+	d, _ := time.ParseDuration(w.MinInterval) // Validated by Load
+	return d
+}
+
+// CacheConfig controls the cache of slow, rarely changing fields such as
+// package counts and the GPU model.
+type CacheConfig struct {
+	TTL        string `json:"ttl"`         // A Go duration such as "30m" or "6h"; "0" disables the cache
+	SlowBudget string `json:"slow_budget"` // Skip fields over this in their last three runs; "0" never skips
+}
+
+// DefaultCacheTTL applies when the config file does not set cache.ttl.
+const DefaultCacheTTL = time.Hour
+
+// CacheTTL returns the configured TTL, or DefaultCacheTTL when unset.
+func (c *Config) CacheTTL() time.Duration {
+	if c.Cache.TTL == "" {
+		return DefaultCacheTTL
+	}
+	ttl, _ := time.ParseDuration(c.Cache.TTL) // Validated by Load
+	return ttl
+}
+
+// DefaultSlowBudget applies when the config file does not set cache.slow_budget.
+const DefaultSlowBudget = 3 * time.Second
+
+// SlowBudget returns the configured budget, or DefaultSlowBudget when unset.
+func (c *Config) SlowBudget() time.Duration {
+	if c.Cache.SlowBudget == "" {
+		return DefaultSlowBudget
+	}
+	budget, _ := time.ParseDuration(c.Cache.SlowBudget) // Validated by Load
+	return budget
+}
+
+// DefaultPath returns $XDG_CONFIG_HOME/kernelview/config.json (or the OS equivalent).
+func DefaultPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "kernelview", "config.json")
+}
+
+// Load reads the config file at path. A missing file at the default location
+// yields an empty Config; an explicitly requested file must exist.
+func Load(path string) (*Config, error) {
+	explicit := path != ""
+	if !explicit {
+		path = DefaultPath()
+	}
+	cfg := &Config{}
+	if path == "" {
+		return cfg, nil
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, os.ErrNotExist) {
+			return cfg, nil
+		}
+		return nil, fmt.Errorf("config: %w", err)
+	}
+	if err := json.Unmarshal(content, cfg); err != nil {
+		return nil, fmt.Errorf("config: %s: %w", path, err)
+	}
+	if cfg.Cache.TTL != "" {
+		if _, err := time.ParseDuration(cfg.Cache.TTL); err != nil {
+			return nil, fmt.Errorf("config: %s: cache.ttl: %w", path, err)
+		}
+	}
+	if cfg.Cache.SlowBudget != "" {
+		if _, err := time.ParseDuration(cfg.Cache.SlowBudget); err != nil {
+			return nil, fmt.Errorf("config: %s: cache.slow_budget: %w", path, err)
+		}
+	}
+	if cfg.Webhook.MinInterval != "" {
+		if _, err := time.ParseDuration(cfg.Webhook.MinInterval); err != nil {
+			return nil, fmt.Errorf("config: %s: webhook.min_interval: %w", path, err)
+		}
+	}
+	switch cfg.Webhook.Format {
+	case "", "generic", "slack", "discord":
+	default:
+		return nil, fmt.Errorf("config: %s: webhook.format: unknown format %q", path, cfg.Webhook.Format)
+	}
+	return cfg, nil
+}
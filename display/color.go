@@ -0,0 +1,143 @@
+package display
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ThemeColors overrides theme colours with hex values such as "#89b4fa" or
+// "#abc" (exported for the config package); empty fields keep the theme's.
+// Accent, which also tints the group rules unless Category is set, may be
+// "random" (a new hue every run) or "distro" (the os-release ANSI_COLOR).
+type ThemeColors struct {
+	Category string `json:"category"`
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Accent   string `json:"accent"`
+}
+
+// colorDepth guesses how many colours the terminal shows: 24 (truecolor),
+// 256 or 16.
+func colorDepth() int {
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return 24
+	}
+	if os.Getenv("WT_SESSION") != "" { // Windows Terminal does not set COLORTERM
+		return 24
+	}
+	if strings.Contains(os.Getenv("TERM"), "256color") {
+		return 256
+	}
+	return 16
+}
+
+// HexColor converts a hex colour to a foreground escape, degrading to the
+// nearest 256 or 16 colour palette entry when the terminal lacks truecolor
+// (exported).
+func HexColor(hex string) (string, error) {
+	s := strings.TrimPrefix(hex, "#")
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if len(s) != 6 || err != nil {
+		return "", fmt.Errorf("colors: %q is not a hex colour like #89b4fa", hex)
+	}
+	r, g, b := int(v>>16), int(v>>8&0xff), int(v&0xff)
+
+	switch colorDepth() {
+	case 24:
+		return fmt.Sprintf("\033[38;2;%d;%d;%dm", r, g, b), nil
+	case 256:
+		return fmt.Sprintf("\033[38;5;%dm", nearestXterm(r, g, b, 16, 256)), nil
+	}
+	n := nearestXterm(r, g, b, 0, 16)
+	if n >= 8 {
+		return fmt.Sprintf("\033[%dm", 90+n-8), nil
+	}
+	return fmt.Sprintf("\033[%dm", 30+n), nil
+}
+
+// nearestXterm finds the palette index in [from, to) closest to r, g, b.
+func nearestXterm(r, g, b, from, to int) int {
+	best, bestDist := from, -1
+	for n := from; n < to; n++ {
+		c := xtermColor(n)
+		dr, dg, db := r-int(c.R), g-int(c.G), b-int(c.B)
+		if dist := dr*dr + dg*dg + db*db; bestDist < 0 || dist < bestDist {
+			best, bestDist = n, dist
+		}
+	}
+	return best
+}
+
+// WithColors returns the theme with the set colours applied.
+func (t Theme) WithColors(c ThemeColors) (Theme, error) {
+	accent := ""
+	switch c.Accent {
+	case "":
+	case "random":
+		accent = randomColor()
+	case "distro":
+		accent = distroColor()
+	default:
+		escape, err := HexColor(c.Accent)
+		if err != nil {
+			return t, err
+		}
+		accent = escape
+	}
+	if accent != "" {
+		t.Accent, t.Category = accent, accent
+	}
+	for _, f := range []struct {
+		hex string
+		dst *string
+	}{{c.Category, &t.Category}, {c.Key, &t.Key}, {c.Value, &t.Value}} {
+		if f.hex == "" {
+			continue
+		}
+		escape, err := HexColor(f.hex)
+		if err != nil {
+			return t, err
+		}
+		*f.dst = escape
+	}
+	return t, nil
+}
+
+// randomColor picks a bright, saturated hue.
+func randomColor() string {
+	h := rand.Float64() * 6
+	x := 1 - math.Abs(math.Mod(h, 2)-1)
+	rgb := [6][3]float64{{1, x, 0}, {x, 1, 0}, {0, 1, x}, {0, x, 1}, {x, 0, 1}, {1, 0, x}}[int(h)]
+	channel := func(v float64) int { return int(255 * (0.35 + 0.65*v)) } // Saturation 0.65
+	escape, _ := HexColor(fmt.Sprintf("#%02x%02x%02x", channel(rgb[0]), channel(rgb[1]), channel(rgb[2])))
+	return escape
+}
+
+// distroColor turns the os-release ANSI_COLOR (SGR parameters such as
+// "0;38;2;60;110;180") into an escape, or "" if the distro sets none.
+func distroColor() string {
+	content, err := os.ReadFile("/etc/os-release")
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		value, ok := strings.CutPrefix(line, "ANSI_COLOR=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(value, `"'`)
+		if value == "" || strings.Trim(value, "0123456789;") != "" {
+			return ""
+		}
+		return "\033[" + value + "m"
+	}
+	return ""
+}
@@ -0,0 +1,46 @@
+package display
+
+import (
+	"os"
+	"strings"
+)
+
+// serialTTYs are device name prefixes of serial and hypervisor consoles,
+// which usually mangle ANSI colours and box drawing.
+var serialTTYs = []string{"/dev/ttyS", "/dev/ttyAMA", "/dev/ttyUSB", "/dev/ttyACM", "/dev/ttymxc", "/dev/hvc"}
+
+// legacyConsole reports whether stdout needs plain ASCII without escape
+// sequences: TERM=dumb (also when piped, as in editor shells), a serial
+// console, or a Windows console that cannot enable VT processing.
+func legacyConsole() bool {
+	if os.Getenv("TERM") == "dumb" {
+		return true
+	}
+	if !isTerminal(os.Stdout) {
+		return false
+	}
+	if tty, err := os.Readlink("/proc/self/fd/1"); err == nil {
+		for _, prefix := range serialTTYs {
+			if strings.HasPrefix(tty, prefix) {
+				return true
+			}
+		}
+	}
+	return !enableVT(os.Stdout)
+}
+
+// asciiBox swaps box drawing (and arrows) for ASCII of the same width, so boxes stay aligned.
+var asciiBox = strings.NewReplacer(
+	"─", "-", "│", "|",
+	"┌", "+", "┐", "+", "└", "+", "┘", "+", "├", "+", "┤", "+",
+	"╭", "+", "╮", "+", "╰", "+", "╯", "+",
+	"↓", "v", "↑", "^",
+)
+
+// plainLines strips the colours from rendered lines and makes the layout ASCII.
+func plainLines(lines []string) []string {
+	for i, line := range lines {
+		lines[i] = asciiBox.Replace(stripAnsi(line))
+	}
+	return lines
+}
@@ -0,0 +1,24 @@
+//go:build !windows
+
+package display
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// enableVT is a no-op outside Windows: Unix terminals interpret ANSI escapes.
+func enableVT(f *os.File) bool {
+	return true
+}
+
+// terminalSize returns the size of the terminal f is attached to in cells,
+// or zeros when it cannot tell.
+func terminalSize(f *os.File) (width, height int) {
+	ws, err := unix.IoctlGetWinsize(int(f.Fd()), unix.TIOCGWINSZ)
+	if err != nil {
+		return 0, 0
+	}
+	return int(ws.Col), int(ws.Row)
+}
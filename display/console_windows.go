@@ -0,0 +1,34 @@
+//go:build windows
+
+package display
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// enableVT turns on ANSI escape processing for a Windows 10+ console. It
+// fails on older consoles (and conhost with legacy mode ticked), which print
+// the escapes literally.
+func enableVT(f *os.File) bool {
+	handle := windows.Handle(f.Fd())
+	var mode uint32
+	if err := windows.GetConsoleMode(handle, &mode); err != nil {
+		return true // Not a console, e.g. a mintty pipe; leave it alone
+	}
+	if mode&windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING != 0 {
+		return true
+	}
+	return windows.SetConsoleMode(handle, mode|windows.ENABLE_VIRTUAL_TERMINAL_PROCESSING) == nil
+}
+
+// terminalSize returns the size of the console window f is attached to in
+// cells, or zeros when it cannot tell.
+func terminalSize(f *os.File) (width, height int) {
+	var info windows.ConsoleScreenBufferInfo
+	if err := windows.GetConsoleScreenBufferInfo(windows.Handle(f.Fd()), &info); err != nil {
+		return 0, 0
+	}
+	return int(info.Window.Right-info.Window.Left) + 1, int(info.Window.Bottom-info.Window.Top) + 1
+}
@@ -0,0 +1,31 @@
+package display
+
+import (
+	"encoding/csv"
+	"io"
+
+	"KernelView-Go/gather"
+)
+
+// WriteCSV writes one host,category,key,value row per populated field after a
+// header row, so results from many hosts can be concatenated into one sheet.
+// A tab separator gives TSV (exported).
+func WriteCSV(w io.Writer, info *gather.SystemInfo, separator rune) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = separator
+	if err := cw.Write([]string{"host", "category", "key", "value"}); err != nil {
+		return err
+	}
+	for _, group := range infoGroups(info) {
+		for _, item := range group.Items {
+			if !hasValue(item.Value) {
+				continue
+			}
+			if err := cw.Write([]string{info.Hostname, group.Category, item.Key, item.Value}); err != nil {
+				return err
+			}
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,34 @@
+package display
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"KernelView-Go/snapshot"
+)
+
+// WriteDiff prints the changes since a snapshot, old values in red and new in
+// green (exported for --diff).
+func WriteDiff(w io.Writer, snap *snapshot.Snapshot, changes []snapshot.Change, theme Theme) {
+	fmt.Fprintf(w, "%sChanges since %s%s\n", theme.Accent, snap.Taken.Local().Format("2006-01-02 15:04"), theme.Reset)
+	if len(changes) == 0 {
+		fmt.Fprintf(w, "%sNo changes.%s\n", theme.Value, theme.Reset)
+		return
+	}
+	for _, c := range changes {
+		fmt.Fprintf(w, "%s%s%s\n", theme.Key, c.Field, theme.Reset)
+		if c.Old != "" {
+			fmt.Fprintf(w, "  \033[31m- %s%s\n", diffLines(c.Old), theme.Reset)
+		}
+		if c.New != "" {
+			fmt.Fprintf(w, "  \033[32m+ %s%s\n", diffLines(c.New), theme.Reset)
+		}
+	}
+}
+
+// diffLines lines up the rest of a multi-line value (one GPU or drive per
+// line) under its first line, past the "- " or "+ " marker.
+func diffLines(value string) string {
+	return strings.ReplaceAll(value, "\n", "\n    ")
+}
@@ -0,0 +1,20 @@
+package display
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"KernelView-Go/snapshot"
+)
+
+func TestWriteDiffIndentsLines(t *testing.T) {
+	var b bytes.Buffer
+	snap := &snapshot.Snapshot{Taken: time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC)}
+	changes := []snapshot.Change{{Field: "GPU", Old: "Intel UHD 620", New: "Intel UHD 620\nNVIDIA MX150"}}
+	WriteDiff(&b, snap, changes, Theme{})
+	if !strings.Contains(b.String(), "+ Intel UHD 620\n    NVIDIA MX150") {
+		t.Errorf("WriteDiff did not line up the second GPU:\n%s", b.String())
+	}
+}
@@ -1,11 +1,13 @@
 package display
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"regexp"
 	"runtime"
+	"slices"
 	"strings"
 
 	"KernelView-Go/gather" // Import the gather package to use SystemInfo
@@ -38,11 +40,33 @@ var (
 	}
 )
 
+// errorColor marks --show-errors values; the themes only style normal output.
+const errorColor = "\033[31m"
+
+var showErrors bool
+
+// SetShowErrors makes DisplaySystemInfo print a row for every field that failed
+// to gather, with the reason, instead of hiding it.
+func SetShowErrors(show bool) {
+	showErrors = show
+}
+
+var clearScreen bool
+
+// SetClear makes DisplaySystemInfo clear the screen first. Scrollback is kept,
+// and nothing is cleared when stdout is not a terminal.
+func SetClear(clear bool) {
+	clearScreen = clear
+}
+
 // --- Internal Helper Functions ---
 
+// ansiRe matches CSI sequences (colours, cursor movement) and OSC sequences
+// (hyperlinks, window titles) terminated by BEL or ST.
+var ansiRe = regexp.MustCompile(`\x1b\[[0-?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)`)
+
 func stripAnsi(s string) string {
-	re := regexp.MustCompile(`\x1b\[[0-9;]*m`)
-	return re.ReplaceAllString(s, "")
+	return ansiRe.ReplaceAllString(s, "")
 }
 
 func Max(x, y int) int {
@@ -56,82 +80,204 @@ func Max(x, y int) int {
 
 // DisplaySystemInfo formats and prints the info (exported).
 func DisplaySystemInfo(info *gather.SystemInfo, theme Theme) {
+	legacy := legacyConsole()
+	clearTerminal(legacy)
+	var l *logo
+	if !legacy {
+		l = loadLogo(logoOptions)
+	}
+	printBlock(info, theme, legacy, l)
+}
+
+// DisplayStream is DisplaySystemInfo for gather.StreamSystemInfo, returning
+// the final result. On a terminal the block is drawn as soon as the first
+// fields arrive and redrawn in place as the rest come in, with "…" for fields
+// still being gathered; until the block has grown taller than the terminal,
+// when only the final one is drawn. Beside an image logo or on a legacy
+// console it waits and prints once.
+func DisplayStream(updates <-chan gather.Update, theme Theme) *gather.SystemInfo {
+	legacy := legacyConsole()
+	var l *logo
+	if !legacy {
+		l = loadLogo(logoOptions)
+	}
+	width, height := terminalSize(os.Stdout)
+	if legacy || l != nil || height == 0 {
+		var info *gather.SystemInfo
+		for u := range updates {
+			info = u.Info
+		}
+		clearTerminal(legacy)
+		printBlock(info, theme, legacy, l)
+		return info
+	}
+
+	clearTerminal(false)
+	fmt.Println()
+	drawn := 0 // Screen rows of the frame on screen
+	for u := range updates {
+		u = latestUpdate(u, updates)
+		frame := renderPending(u.Info, theme, u.Pending)
+		rows := screenRows(frame, width)
+		if !u.Done && rows >= height {
+			continue // Moving the cursor back up would stop at the top of the screen
+		}
+		if drawn > 0 {
+			fmt.Printf("\033[%dA\033[J", drawn) // Back to the frame's first line, and erase it
+		}
+		for _, line := range frame {
+			fmt.Println(line)
+		}
+		drawn = rows
+		if u.Done {
+			fmt.Println()
+			return u.Info
+		}
+	}
+	return nil
+}
+
+// latestUpdate skips to the newest update already waiting, so a burst of
+// fast fields is drawn once.
+func latestUpdate(u gather.Update, updates <-chan gather.Update) gather.Update {
+	for !u.Done {
+		select {
+		case next := <-updates:
+			u = next
+		default:
+			return u
+		}
+	}
+	return u
+}
+
+// screenRows counts the terminal rows lines take up once long ones wrap,
+// including every line of an entry that still holds newlines.
+func screenRows(lines []string, width int) int {
+	rows := 0
+	for _, entry := range lines {
+		for _, line := range strings.Split(entry, "\n") {
+			rows += Max(1, (textWidth(line)+width-1)/width)
+		}
+	}
+	return rows
+}
+
+func clearTerminal(legacy bool) {
+	if !clearScreen || !isTerminal(os.Stdout) || legacy {
+		return
+	}
 	if runtime.GOOS == "windows" {
 		cmd := exec.Command("cmd", "/c", "cls")
 		cmd.Stdout = os.Stdout
 		_ = cmd.Run()
 	} else {
-		fmt.Print("\033[H\033[2J\033[3J") // Clear screen
-	}
-
-	type infoEntry struct{ Key, Value string }
-	groups := []struct {
-		Category string
-		Items    []infoEntry
-	}{
-		{"System", []infoEntry{{"OS", info.OS}, {"Kernel", info.Kernel}, {"Virtualization", info.Virtualization}, {"Uptime", info.Uptime}, {"Shell", info.Shell}, {"Terminal", info.Terminal}}},
-		{"Hardware", []infoEntry{{"CPU", info.CPU}, {"GPU", info.GPU}, {"RAM", info.RAM}}},
-		// {"Network", []infoEntry{{"Hostname", info.Hostname}, {"IP Address", info.IPAddress}, {"Speed", info.NetworkSpeed}}}, // Speed REMOVED
-		{"Network", []infoEntry{{"Hostname", info.Hostname}, {"IP Address", info.IPAddress}}}, // Corrected Network group
-		{"Storage", []infoEntry{{"Disk", info.Disk}, {"Swap", info.Swap}}},
-		{"Display", []infoEntry{{"Resolution", info.Resolution}, {"DE", info.DE}, {"WM", info.WindowManager}}},
-		{"Software", []infoEntry{{"Packages", info.Packages}, {"Languages", info.Languages}, {"Go", info.Go}}},
-		{"CPU Stats", []infoEntry{{"Cores/Threads", info.CoresThreads}, {"Speed", info.CPUSpeed}, {"Usage", info.CPUUsage}, {"Temperature", info.Temperature}}},
-		{"Other", []infoEntry{{"Locale", info.Locale}, {"Ports", info.OpenPorts}}},
-	}
-
-	var formattedLines []string
-	maxKeyLen := 0
-	// Filter and prepare lines first
-	for i := range groups {
-		var groupLines []string
-		groupHasContent := false
-		for _, item := range groups[i].Items {
-			if item.Value != "" && item.Value != "Unknown" && item.Value != "None" && item.Value != "N/A" && item.Value != "0GB/0GB (0.0%)" && item.Value != "0GB / 0GB (0.0%)" && item.Value != "None detected" {
-				if !groupHasContent {
-					groupLines = append(groupLines, fmt.Sprintf("%s─── %s ───%s", theme.Category, groups[i].Category, theme.Reset))
-					groupHasContent = true
-				}
-				if len(item.Key) > maxKeyLen {
-					maxKeyLen = len(item.Key)
-				}
-				groupLines = append(groupLines, fmt.Sprintf("%s:%s", item.Key, item.Value))
-			}
+		fmt.Print("\033[H\033[2J") // Clear screen
+	}
+}
+
+// printBlock prints the rendered info between blank lines, beside l if set.
+func printBlock(info *gather.SystemInfo, theme Theme, legacy bool, l *logo) {
+	if legacy {
+		theme = Theme{}
+		icons = nil // The glyphs would print as garbage
+	}
+	block := renderLines(info, theme)
+	if legacy {
+		block = plainLines(block)
+	}
+
+	// Print the block, beside the image logo when the terminal can draw one
+	fmt.Println()
+	if l != nil {
+		l.printBeside(os.Stdout, block)
+	} else {
+		for _, line := range block {
+			fmt.Println(line)
 		}
-		formattedLines = append(formattedLines, groupLines...)
 	}
+	fmt.Println() // Add a blank line at the bottom
+}
 
-	finalFormattedLines := []string{}
-	maxInfoWidth := 0
-	for _, line := range formattedLines {
-		if strings.Contains(line, "───") { // Header line
-			finalFormattedLines = append(finalFormattedLines, line)
-			if len(stripAnsi(line)) > maxInfoWidth {
-				maxInfoWidth = len(stripAnsi(line))
+// infoEntry is one display row; Field is the SystemInfo field it shows.
+type infoEntry struct {
+	Key, Value, Field string
+	Err               error
+}
+
+type infoGroup struct {
+	Category string
+	Items    []infoEntry
+}
+
+// infoGroups lists every field in display order, including empty ones; use
+// hasValue to filter. The order comes from SetGroups.
+func infoGroups(info *gather.SystemInfo) []infoGroup {
+	result := make([]infoGroup, 0, len(groups))
+	for _, g := range groups {
+		items := make([]infoEntry, 0, len(g.fields))
+		for _, field := range g.fields {
+			r := info.Result(field)
+			items = append(items, infoEntry{Key: tr(fieldLabels[field]), Value: r.Value, Field: field, Err: r.Err})
+		}
+		result = append(result, infoGroup{tr(g.category), items})
+	}
+	return result
+}
+
+// hasValue reports whether a field holds something worth showing.
+func hasValue(v string) bool {
+	return v != "" && v != "Unknown" && v != "None" && v != "N/A" && v != "0GB/0GB (0.0%)" && v != "0GB / 0GB (0.0%)" && v != "None detected"
+}
+
+// renderLines builds the themed title and info lines, without the surrounding
+// blank lines, so the terminal and file exporters lay out the same content.
+func renderLines(info *gather.SystemInfo, theme Theme) []string {
+	return renderPending(info, theme, nil)
+}
+
+// renderPending is renderLines with "…" for the pending fields.
+func renderPending(info *gather.SystemInfo, theme Theme, pending []string) []string {
+	var rows []renderRow
+	for _, group := range infoGroups(info) {
+		groupHasContent := false
+		for _, item := range group.Items {
+			switch {
+			case slices.Contains(pending, item.Field):
+				item.Value = "…"
+			case hasValue(item.Value) || item.Err == nil:
+				item.Value = thresholdColor(info, item.Field) + item.Value
+			case errors.Is(item.Err, gather.ErrSkipped):
+				item.Value = "skipped (timeout)"
+			case errors.Is(item.Err, gather.ErrTooSlow):
+				item.Value = "skipped (slow, see --run-slow)"
+			case showErrors:
+				item.Value = fmt.Sprintf("%serror (%s)", errorColor, item.Err)
 			}
-		} else if strings.Contains(line, ":") { // Key-value line
-			parts := strings.SplitN(line, ":", 2)
-			key := parts[0]
-			value := parts[1]
-			padding := strings.Repeat(" ", maxKeyLen-len(key))
-			formattedLine := fmt.Sprintf("%s%s%s: %s%s%s", theme.Key, key, padding, theme.Value, value, theme.Reset)
-			finalFormattedLines = append(finalFormattedLines, formattedLine)
-			if len(stripAnsi(formattedLine)) > maxInfoWidth {
-				maxInfoWidth = len(stripAnsi(formattedLine))
+			if !hasValue(item.Value) {
+				continue
 			}
+			if !groupHasContent {
+				rows = append(rows, renderRow{header: group.Category})
+				groupHasContent = true
+			}
+			rows = append(rows, renderRow{key: withIcon(item.Field, item.Key), value: item.Value})
 		}
 	}
-
-	// Print Title centered above the info block
-	title := "KernelView Go"
-	if maxInfoWidth > 0 {
-		titleSpacing := Max(0, (maxInfoWidth/2)-(len(title)/2))
-		fmt.Printf("\n%s%s%s%s\n\n", strings.Repeat(" ", titleSpacing), theme.Accent, title, theme.Reset)
+	if len(rows) == 0 {
+		return nil
 	}
 
-	// Print the formatted lines
-	for _, line := range finalFormattedLines {
-		fmt.Println(line)
+	title := titleText(info.Hostname)
+	switch layoutStyle {
+	case "plain":
+		return layoutPlain(rows, theme)
+	case "boxed":
+		return layoutBoxed(title, rows, theme, squareBox)
+	case "rounded":
+		return layoutBoxed(title, rows, theme, roundedBox)
+	case "columns":
+		return layoutColumns(info.Hostname, rows, theme)
 	}
-	fmt.Println() // Add a blank line at the bottom
+	return layoutClassic(title, rows, theme)
 }
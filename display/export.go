@@ -0,0 +1,263 @@
+package display
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/base64"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"KernelView-Go/gather"
+)
+
+// font.png is a glyph atlas rasterized from DejaVu Sans Mono (Bitstream Vera
+// license): 16 glyphs per row, fontCellWidth x fontCellHeight each, in the
+// order of fontRunes.
+//
+//go:embed font.png
+var fontAtlasPNG []byte
+
+const (
+	fontCellWidth  = 10
+	fontCellHeight = 20
+	exportPadding  = 20
+)
+
+var (
+	exportBackground = color.RGBA{0x1d, 0x1f, 0x21, 0xff}
+	exportForeground = color.RGBA{0xc5, 0xc8, 0xc6, 0xff}
+
+	fontOnce   sync.Once
+	fontAtlas  *image.Alpha
+	fontGlyphs map[rune]int
+)
+
+func loadFont() {
+	fontGlyphs = make(map[rune]int)
+	var runes []rune
+	for r := rune(0x20); r <= 0x7e; r++ {
+		runes = append(runes, r)
+	}
+	for r := rune(0xa0); r <= 0xff; r++ {
+		runes = append(runes, r)
+	}
+	runes = append(runes, []rune("─│•…–—→✓✗●┌┐└┘├┤╭╮╯╰")...)
+	for i, r := range runes {
+		fontGlyphs[r] = i
+	}
+
+	img, err := png.Decode(bytes.NewReader(fontAtlasPNG))
+	if err != nil {
+		panic("display: corrupt embedded font: " + err.Error())
+	}
+	fontAtlas = image.NewAlpha(img.Bounds())
+	for y := img.Bounds().Min.Y; y < img.Bounds().Max.Y; y++ {
+		for x := img.Bounds().Min.X; x < img.Bounds().Max.X; x++ {
+			fontAtlas.SetAlpha(x, y, color.Alpha{color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y})
+		}
+	}
+}
+
+// textRun is a stretch of a rendered line sharing one colour.
+type textRun struct {
+	text  string
+	color color.RGBA
+}
+
+// parseANSI splits a themed line into coloured runs, honouring the SGR codes
+// the themes use (16, 256 and 24-bit colour foregrounds and reset).
+func parseANSI(line string) []textRun {
+	var runs []textRun
+	fg := exportForeground
+	for line != "" {
+		esc := strings.Index(line, "\x1b[")
+		if esc < 0 {
+			runs = append(runs, textRun{line, fg})
+			break
+		}
+		if esc > 0 {
+			runs = append(runs, textRun{line[:esc], fg})
+		}
+		end := strings.IndexByte(line[esc:], 'm')
+		if end < 0 {
+			break
+		}
+		params := strings.Split(line[esc+2:esc+end], ";")
+		for i := 0; i < len(params); i++ {
+			n, _ := strconv.Atoi(params[i])
+			switch {
+			case n == 0:
+				fg = exportForeground
+			case n >= 30 && n <= 37:
+				fg = xtermColor(n - 30)
+			case n >= 90 && n <= 97:
+				fg = xtermColor(n - 90 + 8)
+			case n == 38 && i+2 < len(params) && params[i+1] == "5":
+				c, _ := strconv.Atoi(params[i+2])
+				fg = xtermColor(c)
+				i += 2
+			case n == 38 && i+4 < len(params) && params[i+1] == "2":
+				r, _ := strconv.Atoi(params[i+2])
+				g, _ := strconv.Atoi(params[i+3])
+				b, _ := strconv.Atoi(params[i+4])
+				fg = color.RGBA{uint8(r), uint8(g), uint8(b), 0xff}
+				i += 4
+			}
+		}
+		line = line[esc+end+1:]
+	}
+	return runs
+}
+
+// xtermColor maps a 256-colour palette index to the xterm default RGB value.
+func xtermColor(n int) color.RGBA {
+	base := [16]color.RGBA{
+		{0x00, 0x00, 0x00, 0xff}, {0xcd, 0x00, 0x00, 0xff}, {0x00, 0xcd, 0x00, 0xff}, {0xcd, 0xcd, 0x00, 0xff},
+		{0x00, 0x00, 0xee, 0xff}, {0xcd, 0x00, 0xcd, 0xff}, {0x00, 0xcd, 0xcd, 0xff}, {0xe5, 0xe5, 0xe5, 0xff},
+		{0x7f, 0x7f, 0x7f, 0xff}, {0xff, 0x00, 0x00, 0xff}, {0x00, 0xff, 0x00, 0xff}, {0xff, 0xff, 0x00, 0xff},
+		{0x5c, 0x5c, 0xff, 0xff}, {0xff, 0x00, 0xff, 0xff}, {0x00, 0xff, 0xff, 0xff}, {0xff, 0xff, 0xff, 0xff},
+	}
+	switch {
+	case n < 0 || n > 255:
+		return exportForeground
+	case n < 16:
+		return base[n]
+	case n < 232:
+		n -= 16
+		level := func(v int) uint8 {
+			if v == 0 {
+				return 0
+			}
+			return uint8(55 + v*40)
+		}
+		return color.RGBA{level(n / 36), level(n / 6 % 6), level(n % 6), 0xff}
+	}
+	gray := uint8(8 + (n-232)*10)
+	return color.RGBA{gray, gray, gray, 0xff}
+}
+
+// ExportImage renders the themed output, with the configured logo, to a PNG
+// or SVG file chosen by the extension of path (exported).
+func ExportImage(info *gather.SystemInfo, theme Theme, path string) error {
+	lines := renderLines(info, theme)
+	var runs [][]textRun
+	cols := 0
+	for _, line := range lines {
+		runs = append(runs, parseANSI(line))
+		cols = Max(cols, len([]rune(stripAnsi(line))))
+	}
+
+	logoImg := loadLogoImage(logoOptions)
+	textX, rows := exportPadding, len(lines)
+	var logoCols, logoRows int
+	if logoImg != nil {
+		logoCols, logoRows = logoCells(logoOptions, logoImg)
+		textX += (logoCols + 2) * fontCellWidth
+		rows = Max(rows, logoRows)
+	}
+	width := textX + cols*fontCellWidth + exportPadding
+	height := 2*exportPadding + rows*fontCellHeight
+
+	var out []byte
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".png":
+		canvas := image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.Draw(canvas, canvas.Bounds(), image.NewUniform(exportBackground), image.Point{}, draw.Src)
+		if logoImg != nil {
+			scaled := scaleImage(logoImg, logoCols*fontCellWidth, logoRows*fontCellHeight)
+			at := image.Pt(exportPadding, exportPadding)
+			draw.Draw(canvas, scaled.Bounds().Add(at), scaled, image.Point{}, draw.Over)
+		}
+		fontOnce.Do(loadFont)
+		for i, line := range runs {
+			x, y := textX, exportPadding+i*fontCellHeight
+			for _, run := range line {
+				fg := image.NewUniform(run.color)
+				for _, r := range run.text {
+					drawGlyph(canvas, fg, r, x, y)
+					x += fontCellWidth
+				}
+			}
+		}
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, canvas); err != nil {
+			return fmt.Errorf("export: %w", err)
+		}
+		out = buf.Bytes()
+	case ".svg":
+		out = renderSVG(runs, logoImg, logoCols, logoRows, textX, width, height)
+	default:
+		return fmt.Errorf("export: unsupported format %q (use .png or .svg)", filepath.Ext(path))
+	}
+	if err := os.WriteFile(path, out, 0o644); err != nil {
+		return fmt.Errorf("export: %w", err)
+	}
+	return nil
+}
+
+func drawGlyph(dst draw.Image, fg image.Image, r rune, x, y int) {
+	if r == ' ' {
+		return
+	}
+	i, ok := fontGlyphs[r]
+	if !ok {
+		i = fontGlyphs['?']
+	}
+	src := image.Pt(i%16*fontCellWidth, i/16*fontCellHeight)
+	rect := image.Rect(x, y, x+fontCellWidth, y+fontCellHeight)
+	draw.DrawMask(dst, rect, fg, image.Point{}, fontAtlas, src, draw.Over)
+}
+
+// scaleImage resizes img to fit w x h, keeping its aspect ratio (nearest neighbour).
+func scaleImage(img image.Image, w, h int) *image.RGBA {
+	src := img.Bounds()
+	if src.Dx()*h > src.Dy()*w {
+		h = Max(1, src.Dy()*w/src.Dx())
+	} else {
+		w = Max(1, src.Dx()*h/src.Dy())
+	}
+	scaled := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			scaled.Set(x, y, img.At(src.Min.X+x*src.Dx()/w, src.Min.Y+y*src.Dy()/h))
+		}
+	}
+	return scaled
+}
+
+func renderSVG(runs [][]textRun, logoImg image.Image, logoCols, logoRows, textX, width, height int) []byte {
+	var b strings.Builder
+	hex := func(c color.RGBA) string { return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B) }
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n", width, height, width, height)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", hex(exportBackground))
+	if logoImg != nil {
+		var buf bytes.Buffer
+		if png.Encode(&buf, logoImg) == nil {
+			fmt.Fprintf(&b, `<image x="%d" y="%d" width="%d" height="%d" href="data:image/png;base64,%s"/>`+"\n",
+				exportPadding, exportPadding, logoCols*fontCellWidth, logoRows*fontCellHeight, base64.StdEncoding.EncodeToString(buf.Bytes()))
+		}
+	}
+	// 16.6px DejaVu Sans Mono advances 10px per character, matching the PNG grid
+	fmt.Fprintf(&b, `<g font-family="DejaVu Sans Mono, Menlo, Consolas, monospace" font-size="16.6" xml:space="preserve">`+"\n")
+	for i, line := range runs {
+		if len(line) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, `<text x="%d" y="%d">`, textX, exportPadding+i*fontCellHeight+15)
+		for _, run := range line {
+			fmt.Fprintf(&b, `<tspan fill="%s">%s</tspan>`, hex(run.color), html.EscapeString(run.text))
+		}
+		b.WriteString("</text>\n")
+	}
+	b.WriteString("</g>\n</svg>\n")
+	return []byte(b.String())
+}
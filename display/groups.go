@@ -0,0 +1,121 @@
+package display
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GroupOptions places fields in a named group (exported for the config
+// package). Fields are SystemInfo field names, e.g. "IPAddress".
+type GroupOptions struct {
+	Name   string   `json:"name"`
+	Fields []string `json:"fields"`
+}
+
+// fieldGroup is a category and the SystemInfo fields it shows, in order.
+type fieldGroup struct {
+	category string
+	fields   []string
+}
+
+// fieldLabels are the English keys shown for each field.
+var fieldLabels = map[string]string{
+	"OS": "OS", "Kernel": "Kernel", "Arch": "Arch", "KernelModules": "Modules", "KernelTaint": "Taint",
+	"Virtualization": "Virtualization", "RunningVMs": "VMs", "Uptime": "Uptime", "BootTime": "Booted",
+	"PreviousBoots": "Last Boots", "CrashDumps": "Crash Dumps", "JournalErrors": "Journal Errors", "FailedUnits": "Failed Units", "Timezone": "Timezone", "NTPSync": "NTP",
+	"Shell": "Shell", "Terminal": "Terminal", "Session": "Session", "CPU": "CPU", "Board": "Board",
+	"GPU": "GPU", "HybridGraphics": "Hybrid GPU", "GraphicsAPI": "Graphics API", "RAM": "RAM", "Battery": "Battery", "PowerDraw": "Power Draw", "USBDevices": "USB", "InputDevices": "Input", "Camera": "Camera", "Printers": "Printers", "Hostname": "Hostname",
+	"FQDN": "FQDN", "Domain": "Domain", "IPAddress": "IP Address", "NetTraffic": "Traffic", "Bandwidth": "Bandwidth", "Connections": "Connections", "Disk": "Disk", "Drives": "Drives",
+	"Swap": "Swap", "DisplayServer": "Display Server", "Resolution": "Resolution", "DE": "DE", "DisplayManager": "Login", "Portal": "Portal", "Font": "Font", "Wallpaper": "Wallpaper", "Autostart": "Autostart", "ScheduledJobs": "Scheduled",
+	"WindowManager": "WM", "WMPlugins": "WM Plugins", "Packages": "Packages", "Fonts": "Fonts", "LastUpdate": "Updated", "PendingUpdates": "Updates", "Languages": "Languages",
+	"Go": "Go", "Editor": "Editor", "Browser": "Browser", "Compute": "Compute",
+	"CoresThreads": "Cores/Threads", "CPUSpeed": "Speed", "CPUUsage": "Usage", "Temperature": "Temperature",
+	"SoCTemp": "SoC Temp", "Throttling": "Throttling", "Locale": "Locale", "OpenPorts": "Ports",
+	"TopCPU": "Top CPU", "TopMemory": "Top Memory",
+}
+
+// defaultGroups is the built-in display order.
+var defaultGroups = []fieldGroup{
+	{"System", []string{"OS", "Kernel", "Arch", "KernelModules", "KernelTaint", "Virtualization", "RunningVMs", "Uptime", "BootTime", "PreviousBoots", "CrashDumps", "JournalErrors", "FailedUnits", "Timezone", "NTPSync", "Shell", "Terminal", "Session"}},
+	{"Hardware", []string{"CPU", "Board", "GPU", "HybridGraphics", "GraphicsAPI", "RAM", "Battery", "PowerDraw", "InputDevices", "Camera", "Printers", "USBDevices"}},
+	{"Network", []string{"Hostname", "FQDN", "Domain", "IPAddress", "NetTraffic", "Bandwidth", "Connections"}},
+	{"Storage", []string{"Disk", "Drives", "Swap"}},
+	{"Display", []string{"DisplayServer", "Resolution", "DE", "WindowManager", "WMPlugins", "DisplayManager", "Portal", "Font", "Wallpaper"}},
+	{"Software", []string{"Packages", "Fonts", "LastUpdate", "PendingUpdates", "Autostart", "ScheduledJobs", "Languages", "Go", "Editor", "Browser", "Compute"}},
+	{"CPU Stats", []string{"CoresThreads", "CPUSpeed", "CPUUsage", "Temperature", "SoCTemp", "Throttling"}},
+	{"Processes", []string{"TopCPU", "TopMemory"}},
+	{"Other", []string{"Locale", "OpenPorts"}},
+}
+
+var groups = defaultGroups
+
+// SetGroups reorders and regroups the fields. The listed groups come first,
+// in order; a name matching a built-in group (case-insensitively) takes that
+// group's place, any other name starts a new one. Listed fields move into
+// their group, and a built-in group's remaining fields follow its listed
+// ones. Unlisted built-in groups keep their default order after the listed
+// ones and disappear once all their fields have moved.
+//
+// For example, [{"name": "Network"}] puts Network first, and
+// [{"name": "Hardware", "fields": ["CPU", "CoresThreads", "CPUSpeed"]}] moves
+// two CPU Stats fields under CPU.
+func SetGroups(custom []GroupOptions) error {
+	placed := make(map[string]bool)
+	names := make(map[string]bool)
+	for _, g := range custom {
+		if g.Name == "" {
+			return fmt.Errorf("groups: group without a name")
+		}
+		if names[strings.ToLower(g.Name)] {
+			return fmt.Errorf("groups: group %q listed twice", g.Name)
+		}
+		names[strings.ToLower(g.Name)] = true
+		for _, field := range g.Fields {
+			if _, ok := fieldLabels[field]; !ok {
+				return fmt.Errorf("groups: unknown field %q in group %q", field, g.Name)
+			}
+			if placed[field] {
+				return fmt.Errorf("groups: field %q is in more than one group", field)
+			}
+			placed[field] = true
+		}
+	}
+
+	// remaining returns the fields of a built-in group not placed elsewhere.
+	remaining := func(g fieldGroup) []string {
+		var fields []string
+		for _, field := range g.fields {
+			if !placed[field] {
+				fields = append(fields, field)
+				placed[field] = true
+			}
+		}
+		return fields
+	}
+
+	result := make([]fieldGroup, 0, len(defaultGroups)+len(custom))
+	used := make(map[string]bool)
+	for _, g := range custom {
+		group := fieldGroup{category: g.Name, fields: append([]string(nil), g.Fields...)}
+		for _, def := range defaultGroups {
+			if strings.EqualFold(def.category, g.Name) {
+				group.category = def.category // Keep the English name so it is translated
+				group.fields = append(group.fields, remaining(def)...)
+				used[def.category] = true
+			}
+		}
+		if len(group.fields) > 0 {
+			result = append(result, group)
+		}
+	}
+	for _, def := range defaultGroups {
+		if used[def.category] {
+			continue
+		}
+		if fields := remaining(def); len(fields) > 0 {
+			result = append(result, fieldGroup{def.category, fields})
+		}
+	}
+	groups = result
+	return nil
+}
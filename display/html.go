@@ -0,0 +1,68 @@
+package display
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+
+	"KernelView-Go/gather"
+)
+
+// htmlStyle is scoped to .kernelview so fragments can be pasted into other pages.
+const htmlStyle = `<style>
+.kernelview { font-family: ui-monospace, "DejaVu Sans Mono", Menlo, Consolas, monospace; font-size: 14px; color: #c5c8c6; background: #1d1f21; padding: 1em 1.5em; border-radius: 6px; display: inline-block; }
+.kernelview h1 { font-size: 1.2em; color: %s; margin: 0 0 .5em; }
+.kernelview h2 { font-size: 1em; color: %s; margin: 1em 0 .2em; }
+.kernelview dl { display: grid; grid-template-columns: max-content auto; gap: 0 1em; margin: 0; }
+.kernelview dt { color: #eeeeee; }
+.kernelview dd { margin: 0; color: #b2b2b2; white-space: pre-line; }
+</style>
+`
+
+// WriteHTML writes the system info as a styled HTML page, or just the styled
+// <div class="kernelview"> block when fragment is set, for embedding in
+// dashboards and wikis (exported).
+func WriteHTML(w io.Writer, info *gather.SystemInfo, theme Theme, fragment bool) error {
+	bw := bufio.NewWriter(w)
+	if fragment {
+		fmt.Fprintf(bw, htmlStyle, cssColor(theme.Accent), cssColor(theme.Category))
+	} else {
+		fmt.Fprint(bw, "<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n")
+		fmt.Fprintf(bw, "<title>KernelView Go: %s</title>\n", html.EscapeString(info.Hostname))
+		fmt.Fprintf(bw, htmlStyle, cssColor(theme.Accent), cssColor(theme.Category))
+		fmt.Fprint(bw, "</head>\n<body>\n")
+	}
+	fmt.Fprint(bw, "<div class=\"kernelview\">\n<h1>KernelView Go</h1>\n")
+	for _, group := range infoGroups(info) {
+		started := false
+		for _, item := range group.Items {
+			if !hasValue(item.Value) {
+				continue
+			}
+			if !started {
+				fmt.Fprintf(bw, "<h2>%s</h2>\n<dl>\n", html.EscapeString(group.Category))
+				started = true
+			}
+			fmt.Fprintf(bw, "<dt>%s</dt><dd>%s</dd>\n", html.EscapeString(item.Key), html.EscapeString(item.Value))
+		}
+		if started {
+			fmt.Fprint(bw, "</dl>\n")
+		}
+	}
+	fmt.Fprint(bw, "</div>\n")
+	if !fragment {
+		fmt.Fprint(bw, "</body>\n</html>\n")
+	}
+	return bw.Flush()
+}
+
+// cssColor converts a theme's ANSI escape to a CSS colour.
+func cssColor(escape string) string {
+	runs := parseANSI(escape + "x")
+	if len(runs) == 0 {
+		return "inherit"
+	}
+	c := runs[0].color
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
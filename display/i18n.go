@@ -0,0 +1,73 @@
+package display
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Translation catalogs map the English labels and group names to another
+// language; labels missing from a catalog are shown in English.
+//
+//go:embed locales/*.json
+var locales embed.FS
+
+var catalog map[string]string
+
+// Languages lists the supported language codes, English first (exported).
+func Languages() []string {
+	names, _ := fs.Glob(locales, "locales/*.json")
+	langs := make([]string, 0, len(names))
+	for _, name := range names {
+		langs = append(langs, strings.TrimSuffix(strings.TrimPrefix(name, "locales/"), ".json"))
+	}
+	sort.Strings(langs)
+	return append([]string{"en"}, langs...)
+}
+
+// SetLanguage selects the label language, e.g. "de" or "fr_FR.UTF-8". An
+// empty lang follows LC_ALL, LC_MESSAGES or LANG, quietly falling back to
+// English when that language has no catalog; only an explicitly requested
+// language that is not supported is an error.
+func SetLanguage(lang string) error {
+	explicit := lang != ""
+	if !explicit {
+		for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+			if lang = os.Getenv(env); lang != "" {
+				break
+			}
+		}
+	}
+	// "pt_BR.UTF-8" and "pt-BR" both select "pt"
+	lang = strings.ToLower(lang)
+	if i := strings.IndexAny(lang, "_-.@"); i >= 0 {
+		lang = lang[:i]
+	}
+	catalog = nil
+	if lang == "" || lang == "en" || lang == "c" || lang == "posix" {
+		return nil
+	}
+	data, err := locales.ReadFile("locales/" + lang + ".json")
+	if err != nil {
+		if explicit {
+			return fmt.Errorf("lang: unsupported language %q (use %s)", lang, strings.Join(Languages(), ", "))
+		}
+		return nil
+	}
+	if err := json.Unmarshal(data, &catalog); err != nil {
+		panic("display: corrupt embedded catalog " + lang + ": " + err.Error())
+	}
+	return nil
+}
+
+// tr translates an English label.
+func tr(label string) string {
+	if t := catalog[label]; t != "" {
+		return t
+	}
+	return label
+}
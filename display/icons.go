@@ -0,0 +1,81 @@
+package display
+
+import (
+	"fmt"
+	"runtime"
+)
+
+// IconOptions prefixes each key with a glyph (exported for the config package).
+type IconOptions struct {
+	Style string            `json:"style"` // "none" (default), "nerd" (needs a Nerd Font) or "emoji"
+	Map   map[string]string `json:"map"`   // Per-field overrides keyed by SystemInfo field, e.g. {"CPU": "▣"}; "" removes an icon
+}
+
+// osIcons picks the OS glyph for the platform the binary runs on.
+var osIcons = map[string]map[string]string{
+	"nerd":  {"linux": "\uf17c", "darwin": "\uf179", "windows": "\uf17a", "freebsd": "\uf30c"},
+	"emoji": {"linux": "🐧", "darwin": "🍎", "windows": "🪟", "freebsd": "😈"},
+}
+
+// iconSets map SystemInfo field names to glyphs; Nerd Font code points are
+// from the Font Awesome and Material Design ranges of Nerd Fonts 3.
+var iconSets = map[string]map[string]string{
+	"nerd": {
+		"Kernel": "\uf013", "Arch": "\uf085", "KernelModules": "\uf1e6", "KernelTaint": "\uf12a", "Virtualization": "\uf1b2",
+		"RunningVMs": "\uf1b3", "Uptime": "\uf017", "BootTime": "\uf011", "PreviousBoots": "\uf1da",
+		"CrashDumps": "\uf188", "JournalErrors": "\uf0f6", "FailedUnits": "\uf00d", "Timezone": "\uf0ac", "NTPSync": "\uf021", "Shell": "\uf120",
+		"Terminal": "\uf489", "Session": "\uf084", "CPU": "\uf2db", "Board": "\U000f0697", "GPU": "\U000f08ae",
+		"HybridGraphics": "\uf24d", "GraphicsAPI": "\uf1fc", "RAM": "\U000f035b", "Battery": "\uf240", "PowerDraw": "\U000f06a5", "USBDevices": "\uf287", "InputDevices": "\uf11c", "Camera": "\uf030", "Printers": "\uf02f", "Hostname": "\uf233", "FQDN": "\uf1fa",
+		"Domain": "\uf0c0", "IPAddress": "\uf1eb", "NetTraffic": "\uf0dc", "Bandwidth": "\uf0e4", "Connections": "\uf1e0", "Disk": "\uf0a0", "Drives": "\U000f02ca", "Swap": "\uf0ec",
+		"DisplayServer": "\uf26c", "DisplayManager": "\uf2f6", "Portal": "\uf2d2", "Font": "\uf031", "Wallpaper": "\uf03e", "Resolution": "\uf065", "DE": "\uf108", "WindowManager": "\uf2d0",
+		"WMPlugins": "\uf12e", "Packages": "\uf187", "Fonts": "\uf034", "LastUpdate": "\uf073", "PendingUpdates": "\uf019", "Autostart": "\uf04b", "ScheduledJobs": "\uf274", "Languages": "\uf121", "Go": "\ue627",
+		"Editor": "\uf044", "Browser": "\uf14e", "Compute": "\uf0e7", "CoresThreads": "\uf0e8",
+		"CPUSpeed": "\uf0e4", "CPUUsage": "\uf080", "Temperature": "\uf2c9", "SoCTemp": "\uf2c9",
+		"Throttling": "\uf071", "Locale": "\uf1ab", "OpenPorts": "\uf0c1",
+		"TopCPU": "\uf0ae", "TopMemory": "\uf1c0",
+	},
+	"emoji": {
+		"Kernel": "🔩", "Arch": "🧱", "KernelModules": "🔌", "KernelTaint": "🧪", "Virtualization": "💠",
+		"RunningVMs": "🧊", "Uptime": "⏳", "BootTime": "🚀", "PreviousBoots": "📜",
+		"CrashDumps": "💥", "JournalErrors": "🚨", "FailedUnits": "❌", "Timezone": "🌐", "NTPSync": "🔄", "Shell": "🐚",
+		"Terminal": "💻", "Session": "🔐", "CPU": "🧠", "Board": "🟩", "GPU": "🎮",
+		"HybridGraphics": "🔁", "GraphicsAPI": "🎨", "RAM": "🐏", "Battery": "🔋", "PowerDraw": "💡", "USBDevices": "🧷", "InputDevices": "🎹", "Camera": "📷", "Printers": "📠", "Hostname": "🏠", "FQDN": "🔗",
+		"Domain": "🏢", "IPAddress": "📡", "NetTraffic": "📶", "Bandwidth": "🚦", "Connections": "🤝", "Disk": "💾", "Drives": "💽", "Swap": "🔀",
+		"DisplayServer": "📺", "DisplayManager": "🔑", "Portal": "🌀", "Font": "🔠", "Wallpaper": "🌄", "Resolution": "📐", "DE": "🪟", "WindowManager": "🔲",
+		"WMPlugins": "🧩", "Packages": "📦", "Fonts": "🆎", "LastUpdate": "📅", "PendingUpdates": "🆙", "Autostart": "🏁", "ScheduledJobs": "⏰", "Languages": "🔤", "Go": "🐹",
+		"Editor": "📝", "Browser": "🌍", "Compute": "⚡", "CoresThreads": "🧵",
+		"CPUSpeed": "💨", "CPUUsage": "📊", "Temperature": "🔥", "SoCTemp": "🥵",
+		"Throttling": "🐢", "Locale": "💬", "OpenPorts": "🚪",
+		"TopCPU": "🔝", "TopMemory": "🐘",
+	},
+}
+
+var icons map[string]string
+
+// SetIcons selects the key icons drawn by DisplaySystemInfo.
+func SetIcons(opts IconOptions) error {
+	icons = nil
+	switch opts.Style {
+	case "", "none":
+		return nil
+	case "nerd", "emoji":
+	default:
+		return fmt.Errorf("icons: unknown style %q (use nerd, emoji or none)", opts.Style)
+	}
+	icons = map[string]string{"OS": osIcons[opts.Style][runtime.GOOS]}
+	for field, icon := range iconSets[opts.Style] {
+		icons[field] = icon
+	}
+	for field, icon := range opts.Map {
+		icons[field] = icon
+	}
+	return nil
+}
+
+// withIcon prefixes key with the icon for field, if any.
+func withIcon(field, key string) string {
+	if icon := icons[field]; icon != "" {
+		return icon + " " + key
+	}
+	return key
+}
@@ -0,0 +1,232 @@
+package display
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"strings"
+)
+
+// Layout styles for the terminal view and image exports.
+var layoutStyles = []string{"classic", "plain", "boxed", "rounded", "columns"}
+
+var layoutStyle = "classic"
+
+// LayoutStyles lists the accepted --style values, the default first (exported).
+func LayoutStyles() []string {
+	return append([]string(nil), layoutStyles...)
+}
+
+// SetStyle selects how DisplaySystemInfo lays out the info: "classic" (centred
+// title and ─── group rules), "plain" (aligned key: value lines only),
+// "boxed" or "rounded" (a bordered panel) or "columns" (neofetch-like
+// user@host header, no groups).
+func SetStyle(style string) error {
+	if style == "" {
+		style = "classic"
+	}
+	for _, s := range layoutStyles {
+		if s == style {
+			layoutStyle = style
+			return nil
+		}
+	}
+	return fmt.Errorf("style: unknown layout %q (use %s)", style, strings.Join(layoutStyles, ", "))
+}
+
+// TitleOptions customises the title and separators (exported for the config
+// package); empty fields keep the defaults.
+type TitleOptions struct {
+	Text      string `json:"text"`      // "KernelView Go" by default; "user@host" shows user@hostname, "none" hides the title
+	Separator string `json:"separator"` // Drawn either side of group names, "───" by default; "none" leaves the bare name
+	Delimiter string `json:"delimiter"` // Between key and value, ": " by default
+}
+
+var titleOptions TitleOptions
+
+// SetTitle sets the title and separator text drawn by DisplaySystemInfo.
+func SetTitle(opts TitleOptions) {
+	titleOptions = opts
+}
+
+// titleText resolves the title for a host; "" means no title.
+func titleText(hostname string) string {
+	switch titleOptions.Text {
+	case "":
+		return "KernelView Go"
+	case "none":
+		return ""
+	case "user@host":
+		return userName() + "@" + hostname
+	}
+	return titleOptions.Text
+}
+
+// groupHeader puts the separator either side of a group name.
+func groupHeader(name string) string {
+	switch titleOptions.Separator {
+	case "":
+		return "─── " + name + " ───"
+	case "none":
+		return name
+	}
+	return titleOptions.Separator + " " + name + " " + titleOptions.Separator
+}
+
+func delimiter() string {
+	if titleOptions.Delimiter == "" {
+		return ": "
+	}
+	return titleOptions.Delimiter
+}
+
+// userName is the login name, without the DOMAIN\ prefix Windows adds.
+func userName() string {
+	name := os.Getenv("USER")
+	if u, err := user.Current(); err == nil {
+		name = u.Username
+		if i := strings.LastIndexByte(name, '\\'); i >= 0 {
+			name = name[i+1:]
+		}
+	}
+	return name
+}
+
+// renderRow is a group header (header set) or a key/value line.
+type renderRow struct {
+	header     string
+	key, value string
+}
+
+func maxKeyWidth(rows []renderRow) int {
+	width := 0
+	for _, row := range rows {
+		if row.header == "" {
+			width = Max(width, textWidth(row.key))
+		}
+	}
+	return width
+}
+
+// keyValue formats a row with its key padded to width. A value listing one
+// item per line (GPUs, drives, USB devices) gives one line each, the later
+// ones indented to the value column.
+func keyValue(row renderRow, theme Theme, width int) []string {
+	padding := strings.Repeat(" ", width-textWidth(row.key))
+	values := strings.Split(row.value, "\n")
+	lines := []string{fmt.Sprintf("%s%s%s%s%s%s%s", theme.Key, row.key, padding, delimiter(), theme.Value, values[0], theme.Reset)}
+	indent := strings.Repeat(" ", width+textWidth(delimiter()))
+	for _, value := range values[1:] {
+		lines = append(lines, fmt.Sprintf("%s%s%s%s", indent, theme.Value, value, theme.Reset))
+	}
+	return lines
+}
+
+func layoutClassic(title string, rows []renderRow, theme Theme) []string {
+	keyWidth := maxKeyWidth(rows)
+	var lines []string
+	maxInfoWidth := 0
+	for _, row := range rows {
+		rowLines := []string{fmt.Sprintf("%s%s%s", theme.Category, groupHeader(row.header), theme.Reset)}
+		if row.header == "" {
+			rowLines = keyValue(row, theme, keyWidth)
+		}
+		for _, line := range rowLines {
+			lines = append(lines, line)
+			maxInfoWidth = Max(maxInfoWidth, textWidth(line))
+		}
+	}
+
+	if title == "" {
+		return lines
+	}
+
+	// Title centered above the info block
+	titleSpacing := Max(0, (maxInfoWidth/2)-(textWidth(title)/2))
+	block := []string{fmt.Sprintf("%s%s%s%s", strings.Repeat(" ", titleSpacing), theme.Accent, title, theme.Reset), ""}
+	return append(block, lines...)
+}
+
+func layoutPlain(rows []renderRow, theme Theme) []string {
+	keyWidth := maxKeyWidth(rows)
+	var lines []string
+	for _, row := range rows {
+		if row.header == "" {
+			lines = append(lines, keyValue(row, theme, keyWidth)...)
+		}
+	}
+	return lines
+}
+
+type boxChars struct {
+	topLeft, topRight, bottomLeft, bottomRight, teeLeft, teeRight string
+}
+
+var (
+	squareBox  = boxChars{"┌", "┐", "└", "┘", "├", "┤"}
+	roundedBox = boxChars{"╭", "╮", "╰", "╯", "├", "┤"}
+)
+
+// layoutBoxed draws the rows in a panel with the title in the top border and
+// each group as a divider:
+//
+//	┌─ KernelView Go ──────────┐
+//	├─ System ─────────────────┤
+//	│ OS    : Debian 12        │
+//	└──────────────────────────┘
+func layoutBoxed(title string, rows []renderRow, theme Theme, box boxChars) []string {
+	keyWidth := maxKeyWidth(rows)
+	inner := textWidth(title) + 4
+	for _, row := range rows {
+		if row.header != "" {
+			inner = Max(inner, textWidth(row.header)+4)
+		} else {
+			for _, line := range keyValue(row, theme, keyWidth) {
+				inner = Max(inner, textWidth(line)+2)
+			}
+		}
+	}
+	border := func(left, label, labelColor, right string) string {
+		if label == "" {
+			return fmt.Sprintf("%s%s%s%s%s", theme.Category, left, strings.Repeat("─", inner), right, theme.Reset)
+		}
+		fill := strings.Repeat("─", inner-textWidth(label)-3)
+		return fmt.Sprintf("%s%s─ %s%s%s %s%s%s", theme.Category, left, labelColor, label, theme.Category, fill, right, theme.Reset)
+	}
+
+	lines := []string{border(box.topLeft, title, theme.Accent, box.topRight)}
+	for _, row := range rows {
+		if row.header != "" {
+			lines = append(lines, border(box.teeLeft, row.header, theme.Category, box.teeRight))
+			continue
+		}
+		for _, line := range keyValue(row, theme, keyWidth) {
+			padding := strings.Repeat(" ", inner-textWidth(line)-2)
+			lines = append(lines, fmt.Sprintf("%s│%s %s%s %s│%s", theme.Category, theme.Reset, line, padding, theme.Category, theme.Reset))
+		}
+	}
+	bottom := fmt.Sprintf("%s%s%s%s%s", theme.Category, box.bottomLeft, strings.Repeat("─", inner), box.bottomRight, theme.Reset)
+	return append(lines, bottom)
+}
+
+// layoutColumns mimics neofetch: a user@host header underlined with dashes,
+// then unpadded "Key: value" lines with the keys in the accent colour.
+func layoutColumns(hostname string, rows []renderRow, theme Theme) []string {
+	name := userName()
+	header := name + "@" + hostname
+	lines := []string{
+		fmt.Sprintf("%s%s%s@%s%s%s", theme.Accent, name, theme.Reset, theme.Accent, hostname, theme.Reset),
+		strings.Repeat("-", textWidth(header)),
+	}
+	for _, row := range rows {
+		if row.header == "" {
+			values := strings.Split(row.value, "\n")
+			lines = append(lines, fmt.Sprintf("%s%s%s%s%s%s%s", theme.Accent, row.key, theme.Reset, delimiter(), theme.Value, values[0], theme.Reset))
+			indent := strings.Repeat(" ", textWidth(row.key)+textWidth(delimiter()))
+			for _, value := range values[1:] {
+				lines = append(lines, fmt.Sprintf("%s%s%s%s", indent, theme.Value, value, theme.Reset))
+			}
+		}
+	}
+	return lines
+}
@@ -0,0 +1,274 @@
+package display
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color/palette"
+	"image/draw"
+	_ "image/gif" // Registered for image.Decode
+	_ "image/jpeg"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LogoOptions configures the image logo drawn beside the info block (exported
+// for the config package).
+type LogoOptions struct {
+	Source   string `json:"source"`   // "" or "none" (off), "distro", or a path to a PNG/JPEG/GIF
+	Protocol string `json:"protocol"` // "auto" (default), "kitty", "iterm2" or "sixel"
+	Width    int    `json:"width"`    // Width in terminal columns (default 24)
+}
+
+const (
+	defaultLogoWidth = 24
+	// Sixel is sized in pixels; without querying the terminal, assume a common
+	// 10x20 cell so the image roughly fills the requested columns.
+	sixelCellWidth  = 10
+	sixelCellHeight = 20
+)
+
+var logoOptions LogoOptions
+
+// SetLogo installs the logo drawn by DisplaySystemInfo.
+func SetLogo(opts LogoOptions) {
+	logoOptions = opts
+}
+
+type logo struct {
+	img      image.Image
+	protocol string
+	cols     int
+	rows     int
+}
+
+// loadLogo resolves the configured image and a graphics protocol the terminal
+// understands. It returns nil whenever text-only output should be used instead.
+func loadLogo(opts LogoOptions) *logo {
+	if !isTerminal(os.Stdout) {
+		return nil
+	}
+	protocol := opts.Protocol
+	if protocol == "" || protocol == "auto" {
+		protocol = detectGraphicsProtocol()
+	}
+	if protocol != "kitty" && protocol != "iterm2" && protocol != "sixel" {
+		return nil
+	}
+
+	img := loadLogoImage(opts)
+	if img == nil {
+		return nil
+	}
+	cols, rows := logoCells(opts, img)
+	return &logo{img: img, protocol: protocol, cols: cols, rows: rows}
+}
+
+// loadLogoImage decodes the configured logo, or returns nil if there is none.
+func loadLogoImage(opts LogoOptions) image.Image {
+	path := opts.Source
+	if path == "distro" {
+		path = findDistroLogo()
+	}
+	if path == "" || path == "none" {
+		return nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil || img.Bounds().Empty() {
+		return nil
+	}
+	return img
+}
+
+// logoCells sizes the logo in terminal cells, keeping its aspect ratio.
+func logoCells(opts LogoOptions, img image.Image) (int, int) {
+	b := img.Bounds()
+	cols := opts.Width
+	if cols <= 0 {
+		cols = defaultLogoWidth
+	}
+	// Cells are about twice as tall as they are wide
+	rows := Max(1, (cols*b.Dy()*sixelCellWidth+b.Dx()*sixelCellHeight-1)/(b.Dx()*sixelCellHeight))
+	return cols, rows
+}
+
+// detectGraphicsProtocol guesses from the environment, since querying the
+// terminal would need raw mode. Multiplexers pass none of these through.
+func detectGraphicsProtocol() string {
+	if os.Getenv("TMUX") != "" || strings.HasPrefix(os.Getenv("TERM"), "screen") {
+		return ""
+	}
+	term := os.Getenv("TERM")
+	termProgram := os.Getenv("TERM_PROGRAM")
+	switch {
+	case os.Getenv("KITTY_WINDOW_ID") != "", term == "xterm-kitty", term == "xterm-ghostty", termProgram == "ghostty":
+		return "kitty"
+	case termProgram == "iTerm.app", termProgram == "WezTerm", os.Getenv("LC_TERMINAL") == "iTerm2":
+		return "iterm2"
+	case term == "foot", strings.HasPrefix(term, "foot-"), term == "mlterm", term == "yaft-256color", term == "contour", strings.Contains(term, "sixel"):
+		return "sixel"
+	}
+	return ""
+}
+
+// findDistroLogo looks up the os-release LOGO icon name, falling back to "<ID>-logo".
+func findDistroLogo() string {
+	content, err := os.ReadFile("/etc/os-release")
+	if err != nil {
+		return ""
+	}
+	var names []string
+	var id string
+	for _, line := range strings.Split(string(content), "\n") {
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(value, `"'`)
+		switch key {
+		case "LOGO":
+			names = append([]string{value}, names...)
+		case "ID":
+			id = value
+		}
+	}
+	if id != "" {
+		names = append(names, id+"-logo", id)
+	}
+	for _, name := range names {
+		candidates := []string{filepath.Join("/usr/share/pixmaps", name+".png")}
+		for _, size := range []string{"256x256", "128x128", "96x96", "64x64", "48x48"} {
+			candidates = append(candidates, filepath.Join("/usr/share/icons/hicolor", size, "apps", name+".png"))
+		}
+		for _, path := range candidates {
+			if _, err := os.Stat(path); err == nil {
+				return path
+			}
+		}
+	}
+	return ""
+}
+
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// printBeside draws the logo at the cursor and prints lines to its right.
+func (l *logo) printBeside(w io.Writer, lines []string) {
+	bw := bufio.NewWriter(w)
+	defer bw.Flush()
+
+	fmt.Fprint(bw, "\0337") // Save cursor; the image may move it
+	switch l.protocol {
+	case "kitty":
+		l.writeKitty(bw)
+	case "iterm2":
+		l.writeITerm2(bw)
+	case "sixel":
+		writeSixel(bw, l.img, l.cols*sixelCellWidth)
+	}
+	fmt.Fprint(bw, "\0338")
+
+	indent := fmt.Sprintf("\033[%dC", l.cols+2)
+	for _, line := range lines {
+		fmt.Fprintln(bw, indent+line)
+	}
+	for i := len(lines); i < l.rows; i++ { // Leave the cursor below the image
+		fmt.Fprintln(bw)
+	}
+}
+
+func (l *logo) encodePNG() string {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, l.img); err != nil {
+		return ""
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+// writeKitty sends the image as PNG in 4 KiB chunks; C=1 keeps the cursor in place.
+func (l *logo) writeKitty(w io.Writer) {
+	data := l.encodePNG()
+	for first := true; first || data != ""; first = false {
+		chunk := data
+		if len(chunk) > 4096 {
+			chunk = chunk[:4096]
+		}
+		data = data[len(chunk):]
+		more := 0
+		if data != "" {
+			more = 1
+		}
+		if first {
+			fmt.Fprintf(w, "\033_Gf=100,a=T,C=1,c=%d,r=%d,m=%d;%s\033\\", l.cols, l.rows, more, chunk)
+		} else {
+			fmt.Fprintf(w, "\033_Gm=%d;%s\033\\", more, chunk)
+		}
+	}
+}
+
+func (l *logo) writeITerm2(w io.Writer) {
+	fmt.Fprintf(w, "\033]1337;File=inline=1;width=%d;height=%d;preserveAspectRatio=1:%s\a", l.cols, l.rows, l.encodePNG())
+}
+
+// writeSixel scales img to width pixels, dithers it to the Plan 9 palette and
+// emits it as DEC sixel data. Mostly transparent pixels are left unpainted.
+func writeSixel(w io.Writer, img image.Image, width int) {
+	src := img.Bounds()
+	scaled := scaleImage(img, width, Max(1, src.Dy()*width/src.Dx()))
+	height := scaled.Bounds().Dy()
+	paletted := image.NewPaletted(scaled.Bounds(), palette.Plan9)
+	draw.FloydSteinberg.Draw(paletted, paletted.Bounds(), scaled, image.Point{})
+
+	fmt.Fprintf(w, "\033P0;1;0q\"1;1;%d;%d", width, height)
+	for i, c := range palette.Plan9 {
+		r, g, b, _ := c.RGBA()
+		fmt.Fprintf(w, "#%d;2;%d;%d;%d", i, r*100/0xffff, g*100/0xffff, b*100/0xffff)
+	}
+	for band := 0; band < height; band += 6 {
+		// Collect the sixel bits of every colour present in this band
+		bits := make(map[uint8][]byte)
+		for dy := 0; dy < 6 && band+dy < height; dy++ {
+			for x := 0; x < width; x++ {
+				if scaled.RGBAAt(x, band+dy).A < 0x80 {
+					continue
+				}
+				idx := paletted.ColorIndexAt(x, band+dy)
+				if bits[idx] == nil {
+					bits[idx] = make([]byte, width)
+				}
+				bits[idx][x] |= 1 << dy
+			}
+		}
+		for idx, row := range bits {
+			fmt.Fprintf(w, "#%d", idx)
+			for x := 0; x < width; {
+				run := 1
+				for x+run < width && row[x+run] == row[x] {
+					run++
+				}
+				ch := byte(63 + row[x])
+				if run > 3 {
+					fmt.Fprintf(w, "!%d%c", run, ch)
+				} else {
+					w.Write(bytes.Repeat([]byte{ch}, run))
+				}
+				x += run
+			}
+			fmt.Fprint(w, "$") // Carriage return to overlay the next colour
+		}
+		fmt.Fprint(w, "-") // Next band
+	}
+	fmt.Fprint(w, "\033\\")
+}
@@ -0,0 +1,106 @@
+package display
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"KernelView-Go/gather"
+)
+
+// OneLineOptions configures --oneline (exported for the config package).
+type OneLineOptions struct {
+	Fields    []string `json:"fields"`    // SystemInfo field names; DefaultOneLineFields when empty
+	Separator string   `json:"separator"` // " | " by default
+}
+
+// DefaultOneLineFields make a summary short enough for a tmux status bar.
+var DefaultOneLineFields = []string{"OS", "Kernel", "CPU", "RAM", "Disk", "Uptime"}
+
+var (
+	cpuNoise    = regexp.MustCompile(`\((R|TM)\)|\s+@.*$|\s+(CPU|Processor)\b|^\d+(st|nd|rd|th) Gen\s+`)
+	usageLabels = map[string]string{"RAM": "RAM", "Disk": "disk", "Swap": "swap"}
+)
+
+// compactValue shortens a field's value for the one-line summary, e.g.
+// "0.3GB / 5.9GB (6%)" to "6% RAM" and "3 days, 4 hours" to "up 3d 4h".
+func compactValue(info *gather.SystemInfo, field, value string) string {
+	value = strings.ReplaceAll(value, "\n", ", ") // One GPU or drive per line
+	switch field {
+	case "OS":
+		value, _, _ = strings.Cut(value, " (")
+		return strings.Replace(value, " GNU/Linux", "", 1)
+	case "Kernel":
+		return value[strings.LastIndexByte(value, ' ')+1:]
+	case "CPU":
+		return strings.Join(strings.Fields(cpuNoise.ReplaceAllString(value, "")), " ")
+	case "Uptime":
+		d := info.Metrics.Uptime
+		switch days, hours := int(d.Hours())/24, int(d.Hours())%24; {
+		case days > 0:
+			return fmt.Sprintf("up %dd %dh", days, hours)
+		case hours > 0:
+			return fmt.Sprintf("up %dh %dm", hours, int(d.Minutes())%60)
+		}
+		return fmt.Sprintf("up %dm", int(d.Minutes()))
+	}
+	if label, ok := usageLabels[field]; ok {
+		if percent, ok := thresholdFields[field].read(&info.Metrics); ok {
+			return fmt.Sprintf("%.0f%% %s", percent, label)
+		}
+	}
+	return value
+}
+
+// WriteOneLine prints the chosen fields on one uncoloured line, skipping
+// empty ones (exported).
+func WriteOneLine(w io.Writer, info *gather.SystemInfo, opts OneLineOptions) error {
+	fields := opts.Fields
+	if len(fields) == 0 {
+		fields = DefaultOneLineFields
+	}
+	separator := opts.Separator
+	if separator == "" {
+		separator = " | "
+	}
+	var parts []string
+	for _, field := range fields {
+		if _, ok := fieldLabels[field]; !ok {
+			return fmt.Errorf("oneline: unknown field %q", field)
+		}
+		if value := info.Result(field).Value; hasValue(value) {
+			parts = append(parts, compactValue(info, field, value))
+		}
+	}
+	_, err := fmt.Fprintln(w, strings.Join(parts, separator))
+	return err
+}
+
+// WriteGreeting prints the two-line --greet summary for shell rc files:
+// user@host with the OS, kernel and uptime, then memory, disk and IP
+// (exported).
+func WriteGreeting(w io.Writer, info *gather.SystemInfo, theme Theme) error {
+	if legacyConsole() {
+		theme = Theme{}
+	}
+	line := func(fields ...string) string {
+		var parts []string
+		for _, field := range fields {
+			if value := info.Result(field).Value; hasValue(value) {
+				color := ""
+				if theme != (Theme{}) {
+					color = thresholdColor(info, field)
+				}
+				parts = append(parts, theme.Value+color+compactValue(info, field, value)+theme.Reset)
+			}
+		}
+		return strings.Join(parts, " · ")
+	}
+	header := fmt.Sprintf("%s%s@%s%s", theme.Accent, userName(), info.Hostname, theme.Reset)
+	if rest := line("OS", "Kernel", "Uptime"); rest != "" {
+		header += " · " + rest
+	}
+	_, err := fmt.Fprintf(w, "%s\n%s\n", header, line("RAM", "Disk", "IPAddress"))
+	return err
+}
@@ -0,0 +1,143 @@
+package display
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strconv"
+	"time"
+)
+
+// CaptureScreenshot asks xdg-desktop-portal for a screenshot (the user picks the
+// terminal window in the portal dialog) and copies it into the current
+// directory as kernelview-<timestamp>.png. Returns the saved path (exported).
+func CaptureScreenshot() (string, error) {
+	if runtime.GOOS != "linux" {
+		return "", errors.New("screenshot: only supported on desktop Linux")
+	}
+	if os.Getenv("WAYLAND_DISPLAY") == "" && os.Getenv("DISPLAY") == "" {
+		return "", errors.New("screenshot: no graphical session")
+	}
+	if _, err := exec.LookPath("gdbus"); err != nil {
+		return "", errors.New("screenshot: gdbus not found")
+	}
+
+	// Portal results arrive as a Response signal on the request object, so
+	// start listening before making the call.
+	monitor := exec.Command("gdbus", "monitor", "--session", "--dest", "org.freedesktop.portal.Desktop")
+	stdout, err := monitor.StdoutPipe()
+	if err != nil {
+		return "", err
+	}
+	if err := monitor.Start(); err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = monitor.Process.Kill()
+		_ = monitor.Wait()
+	}()
+	lines := bufio.NewScanner(stdout)
+	if !lines.Scan() { // "Monitoring signals from all objects owned by ..."
+		return "", errors.New("screenshot: xdg-desktop-portal is not running")
+	}
+
+	token := "kernelview" + strconv.Itoa(os.Getpid())
+	call := exec.Command("gdbus", "call", "--session",
+		"--dest", "org.freedesktop.portal.Desktop",
+		"--object-path", "/org/freedesktop/portal/desktop",
+		"--method", "org.freedesktop.portal.Screenshot.Screenshot",
+		"", fmt.Sprintf("{'handle_token': <'%s'>, 'interactive': <true>}", token))
+	if out, err := call.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("screenshot: portal call failed: %s", out)
+	}
+
+	type response struct {
+		code uint32
+		uri  string
+	}
+	responses := make(chan response, 1)
+	go func() {
+		for lines.Scan() {
+			if code, uri, ok := portalResponse(lines.Text(), token); ok {
+				responses <- response{code, uri}
+				return
+			}
+		}
+		close(responses) // gdbus monitor exited
+	}()
+
+	var uri string
+	select {
+	case r, ok := <-responses:
+		switch {
+		case !ok:
+			return "", errors.New("screenshot: lost the connection to the portal")
+		case r.code == 1:
+			return "", errors.New("screenshot: cancelled")
+		case r.code != 0:
+			return "", fmt.Errorf("screenshot: the portal failed (response %d)", r.code)
+		}
+		uri = r.uri
+	case <-time.After(2 * time.Minute): // Interactive dialogs wait on the user
+		return "", errors.New("screenshot: timed out waiting for the portal")
+	}
+
+	parsed, err := url.Parse(uri)
+	if err != nil || parsed.Scheme != "file" {
+		return "", fmt.Errorf("screenshot: unexpected portal uri %q", uri)
+	}
+	dest := fmt.Sprintf("kernelview-%s%s", time.Now().Format("20060102-150405"), filepath.Ext(parsed.Path))
+	if err := copyFile(parsed.Path, dest); err != nil {
+		return "", fmt.Errorf("screenshot: %w", err)
+	}
+	return dest, nil
+}
+
+var (
+	portalResponseRe = regexp.MustCompile(`request/\S*?(\w+): org\.freedesktop\.portal\.Request\.Response \(uint32 (\d+),`)
+	portalURIRe      = regexp.MustCompile(`'uri': <'([^']+)'>`)
+)
+
+// portalResponse parses the Response signal for the request named token from
+// a gdbus monitor line. The code is 0 on success, when the results carry the
+// uri, 1 when the user cancelled and 2 when the request failed otherwise.
+func portalResponse(line, token string) (code uint32, uri string, ok bool) {
+	m := portalResponseRe.FindStringSubmatch(line)
+	if m == nil || m[1] != token {
+		return 0, "", false
+	}
+	n, err := strconv.ParseUint(m[2], 10, 32)
+	if err != nil {
+		return 0, "", false
+	}
+	if n == 0 {
+		if u := portalURIRe.FindStringSubmatch(line); u != nil {
+			uri = u[1]
+		}
+	}
+	return uint32(n), uri, true
+}
+
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
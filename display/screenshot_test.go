@@ -0,0 +1,29 @@
+package display
+
+import "testing"
+
+func TestPortalResponse(t *testing.T) {
+	const path = "/org/freedesktop/portal/desktop/request/1_42/"
+	tests := []struct {
+		name   string
+		line   string
+		code   uint32
+		uri    string
+		wantOK bool
+	}{
+		{"success", path + "kernelview123: org.freedesktop.portal.Request.Response (uint32 0, {'uri': <'file:///home/me/Pictures/Screenshot.png'>})",
+			0, "file:///home/me/Pictures/Screenshot.png", true},
+		{"cancelled", path + "kernelview123: org.freedesktop.portal.Request.Response (uint32 1, @a{sv} {})", 1, "", true},
+		{"failed", path + "kernelview123: org.freedesktop.portal.Request.Response (uint32 2, @a{sv} {})", 2, "", true},
+		{"other request", path + "kernelview999: org.freedesktop.portal.Request.Response (uint32 0, {'uri': <'file:///x.png'>})", 0, "", false},
+		{"other signal", path + "kernelview123: org.freedesktop.portal.Request.Closed ()", 0, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, uri, ok := portalResponse(tt.line, "kernelview123")
+			if ok != tt.wantOK || code != tt.code || uri != tt.uri {
+				t.Errorf("portalResponse() = %d, %q, %v; want %d, %q, %v", code, uri, ok, tt.code, tt.uri, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package display
+
+import (
+	"fmt"
+	"io"
+
+	"KernelView-Go/gather"
+)
+
+// WriteSensors lists every temperature sensor by group, as sorted by the
+// gatherer (exported for --sensors).
+func WriteSensors(w io.Writer, info *gather.SystemInfo) {
+	sensors := info.Metrics.Sensors
+	if len(sensors) == 0 {
+		fmt.Fprintln(w, "Sensors: none found")
+		return
+	}
+	width := 0
+	for _, s := range sensors {
+		width = Max(width, textWidth(s.Label))
+	}
+	fmt.Fprintln(w, "Sensors:")
+	group := ""
+	for _, s := range sensors {
+		if s.Group != group {
+			group = s.Group
+			fmt.Fprintf(w, "  %s\n", group)
+		}
+		fmt.Fprintf(w, "    %-*s %6.1f °C\n", width, s.Label, s.Celsius)
+	}
+}
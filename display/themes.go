@@ -0,0 +1,65 @@
+package display
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// namedThemes are the built-in --theme palettes, after each scheme's
+// published colours.
+var namedThemes = map[string]ThemeColors{
+	"dracula":   {Category: "#bd93f9", Key: "#8be9fd", Value: "#f8f8f2", Accent: "#ff79c6"},
+	"gruvbox":   {Category: "#fabd2f", Key: "#83a598", Value: "#ebdbb2", Accent: "#fb4934"},
+	"nord":      {Category: "#81a1c1", Key: "#88c0d0", Value: "#d8dee9", Accent: "#8fbcbb"},
+	"solarized": {Category: "#268bd2", Key: "#2aa198", Value: "#93a1a1", Accent: "#b58900"},
+}
+
+// monoTheme uses bold instead of colour.
+var monoTheme = Theme{Category: "\033[1m", Accent: "\033[1m", Reset: "\033[0m"}
+
+// ThemeNames lists the accepted --theme values, "default" first (exported).
+func ThemeNames() []string {
+	names := []string{"mono"}
+	for name := range namedThemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return append([]string{"default"}, names...)
+}
+
+// Named returns the built-in theme called name; "" and "default" return t.
+func (t Theme) Named(name string) (Theme, error) {
+	switch name {
+	case "", "default":
+		return t, nil
+	case "mono":
+		return monoTheme, nil
+	}
+	colors, ok := namedThemes[name]
+	if !ok {
+		return t, fmt.Errorf("theme: unknown theme %q (use %s)", name, strings.Join(ThemeNames(), ", "))
+	}
+	return t.WithColors(colors)
+}
+
+// ListThemes prints a one-line sample of every theme, built on base (exported).
+func ListThemes(w io.Writer, base Theme) error {
+	names := ThemeNames()
+	width := 0
+	for _, name := range names {
+		width = Max(width, len(name))
+	}
+	for _, name := range names {
+		t, err := base.Named(name)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(w, "%s%-*s%s  %s─── System ───%s  %sOS%s: %sLinux%s\n",
+			t.Accent, width, name, t.Reset, t.Category, t.Reset, t.Key, t.Reset, t.Value, t.Reset); err != nil {
+			return err
+		}
+	}
+	return nil
+}
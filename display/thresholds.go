@@ -0,0 +1,114 @@
+package display
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"KernelView-Go/gather"
+)
+
+// Threshold colours a usage value yellow from Warn and red from Crit
+// (exported for the config package); below Warn it is green. A zero
+// Threshold leaves the value uncoloured.
+type Threshold struct {
+	Warn float64 `json:"warn"`
+	Crit float64 `json:"crit"`
+}
+
+// Thresholds are keyed by metric: disk, ram, swap (percent used), cpu
+// (percent) and temp (°C, for both CPU and SoC sensors).
+type Thresholds map[string]Threshold
+
+// DefaultThresholds apply to metrics the config file does not set.
+var DefaultThresholds = Thresholds{
+	"disk": {Warn: 75, Crit: 90},
+	"ram":  {Warn: 75, Crit: 90},
+	"swap": {Warn: 50, Crit: 80},
+	"cpu":  {Warn: 70, Crit: 90},
+	"temp": {Warn: 70, Crit: 85},
+}
+
+const (
+	okColor   = "\033[32m"
+	warnColor = "\033[33m"
+	critColor = "\033[31m"
+)
+
+var (
+	// thresholdFields maps SystemInfo fields to their metric and reading.
+	thresholdFields = map[string]struct {
+		metric string
+		read   func(m *gather.Metrics) (float64, bool)
+	}{
+		"Disk":        {"disk", func(m *gather.Metrics) (float64, bool) { return usagePercent(m.Disk) }},
+		"RAM":         {"ram", func(m *gather.Metrics) (float64, bool) { return usagePercent(m.RAM) }},
+		"Swap":        {"swap", func(m *gather.Metrics) (float64, bool) { return usagePercent(m.Swap) }},
+		"CPUUsage":    {"cpu", func(m *gather.Metrics) (float64, bool) { return reading(m.CPUUsage) }},
+		"Temperature": {"temp", func(m *gather.Metrics) (float64, bool) { return reading(m.Temperature) }},
+		"SoCTemp":     {"temp", func(m *gather.Metrics) (float64, bool) { return reading(m.SoCTemp) }},
+	}
+
+	thresholds = DefaultThresholds
+)
+
+func usagePercent(u *gather.Usage) (float64, bool) {
+	if u == nil {
+		return 0, false
+	}
+	return u.Percent, true
+}
+
+func reading(v *float64) (float64, bool) {
+	if v == nil {
+		return 0, false
+	}
+	return *v, true
+}
+
+// SetThresholds overrides the default thresholds per metric.
+func SetThresholds(t Thresholds) error {
+	merged := make(Thresholds, len(DefaultThresholds))
+	for metric, th := range DefaultThresholds {
+		merged[metric] = th
+	}
+	for metric, th := range t {
+		if _, ok := DefaultThresholds[metric]; !ok {
+			var names []string
+			for name := range DefaultThresholds {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return fmt.Errorf("thresholds: unknown metric %q (use %s)", metric, strings.Join(names, ", "))
+		}
+		if th.Warn > th.Crit {
+			return fmt.Errorf("thresholds: %s warn (%g) is above crit (%g)", metric, th.Warn, th.Crit)
+		}
+		merged[metric] = th
+	}
+	thresholds = merged
+	return nil
+}
+
+// thresholdColor returns the colour for a field's reading, or "" if the
+// field has no threshold or was not read.
+func thresholdColor(info *gather.SystemInfo, field string) string {
+	f, ok := thresholdFields[field]
+	if !ok {
+		return ""
+	}
+	th := thresholds[f.metric]
+	if th == (Threshold{}) {
+		return ""
+	}
+	v, ok := f.read(&info.Metrics)
+	switch {
+	case !ok:
+		return ""
+	case v >= th.Crit:
+		return critColor
+	case v >= th.Warn:
+		return warnColor
+	}
+	return okColor
+}
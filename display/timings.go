@@ -0,0 +1,55 @@
+package display
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"KernelView-Go/gather"
+)
+
+// WriteTimings prints how long each gatherer took, slowest first, followed by
+// the total wall time (exported for --timings).
+func WriteTimings(w io.Writer, info *gather.SystemInfo, total time.Duration) {
+	names := make([]string, 0, len(info.Timings))
+	width := len("Total")
+	for name := range info.Timings {
+		names = append(names, name)
+		width = Max(width, len(name))
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return info.Timings[names[i]] > info.Timings[names[j]]
+	})
+
+	fmt.Fprintln(w, "Timings (gatherers run in parallel):")
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-*s %8.1f ms\n", width, name, float64(info.Timings[name].Microseconds())/1000)
+	}
+	fmt.Fprintf(w, "  %-*s %8.1f ms\n", width, "Total", float64(total.Microseconds())/1000)
+}
+
+// WriteSources prints how each gathered field was obtained, in SystemInfo
+// order (exported for --sources).
+func WriteSources(w io.Writer, info *gather.SystemInfo) {
+	width := 0
+	for field := range info.Sources {
+		width = Max(width, len(field))
+	}
+	fmt.Fprintln(w, "Sources:")
+	for _, field := range gather.FieldNames() {
+		src, ok := info.Sources[field]
+		if !ok {
+			continue
+		}
+		reads := strings.Join(src.Reads, ", ")
+		switch {
+		case src.Cached:
+			reads = "cache"
+		case reads == "":
+			reads = "Go runtime and environment"
+		}
+		fmt.Fprintf(w, "  %-*s %s\n", width, field, reads)
+	}
+}
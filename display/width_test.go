@@ -0,0 +1,23 @@
+package display
+
+import "testing"
+
+func TestTextWidth(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"", 0},
+		{"Debian 12", 9},
+		{"\033[38;5;255mOS\033[0m", 2},
+		{"\033]8;;https://example.com\007link\033]8;;\007", 4},
+		{"日本語", 6},
+		{"📦 Packages", 11},
+		{"café", 4},
+	}
+	for _, tt := range tests {
+		if got := textWidth(tt.s); got != tt.want {
+			t.Errorf("textWidth(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package gather
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// getAutostart counts the applications started at login, e.g. "14 (5 user,
+// 9 system)": XDG autostart entries, macOS LaunchAgents, or the Windows Run
+// keys and Startup folders.
+func getAutostart(ctx context.Context) (string, error) {
+	var user, system int
+	switch runtime.GOOS {
+	case "darwin":
+		home, _ := os.UserHomeDir()
+		user = countFiles(filepath.Join(home, "Library/LaunchAgents", "*.plist"))
+		system = countFiles("/Library/LaunchAgents/*.plist")
+	case "windows":
+		user, system = windowsAutostart()
+	default:
+		user, system = xdgAutostart()
+	}
+	if user+system == 0 {
+		return "None", nil
+	}
+	return fmt.Sprintf("%d (%d user, %d system)", user+system, user, system), nil
+}
+
+func countFiles(pattern string) int {
+	traceRead("file", pattern)
+	matches, _ := filepath.Glob(pattern)
+	return len(matches)
+}
+
+// xdgAutostart counts the .desktop entries that will start in this desktop.
+// A user entry replaces the system one of the same name, and entries can be
+// disabled with Hidden=true or limited to desktops with OnlyShowIn/NotShowIn.
+func xdgAutostart() (user, system int) {
+	entries := make(map[string]string) // Name to path; user entries win
+	for _, dir := range []string{"/etc/xdg/autostart", filepath.Join(configHome(), "autostart")} {
+		traceRead("file", filepath.Join(dir, "*.desktop"))
+		paths, _ := filepath.Glob(filepath.Join(dir, "*.desktop"))
+		for _, path := range paths {
+			entries[filepath.Base(path)] = path
+		}
+	}
+	desktops := strings.Split(os.Getenv("XDG_CURRENT_DESKTOP"), ":")
+	for _, path := range entries {
+		if !autostartEnabled(path, desktops) {
+			continue
+		}
+		if strings.HasPrefix(path, "/etc/") {
+			system++
+		} else {
+			user++
+		}
+	}
+	return user, system
+}
+
+func autostartEnabled(path string, desktops []string) bool {
+	entry := func(key string) string { return configValue(path, "Desktop Entry", key) }
+	if entry("Hidden") == "true" {
+		return false
+	}
+	inList := func(list string) bool {
+		return slices.ContainsFunc(strings.Split(list, ";"), func(d string) bool { return d != "" && slices.Contains(desktops, d) })
+	}
+	if only := entry("OnlyShowIn"); only != "" && !inList(only) {
+		return false
+	}
+	return !inList(entry("NotShowIn"))
+}
+
+// windowsAutostart counts the Run key values and Startup folder shortcuts of
+// the user and of all users.
+func windowsAutostart() (user, system int) {
+	const runKey = `SOFTWARE\Microsoft\Windows\CurrentVersion\Run`
+	const startup = `Microsoft\Windows\Start Menu\Programs\Startup`
+	count := func(key, folder string) int {
+		names, _ := registryValueNames(key)
+		n := len(names)
+		traceRead("file", folder)
+		files, _ := os.ReadDir(folder)
+		for _, f := range files {
+			if !strings.EqualFold(f.Name(), "desktop.ini") {
+				n++
+			}
+		}
+		return n
+	}
+	user = count(`HKCU\`+runKey, filepath.Join(os.Getenv("APPDATA"), startup))
+	system = count(`HKLM\`+runKey, filepath.Join(os.Getenv("ProgramData"), startup))
+	return user, system
+}
@@ -0,0 +1,93 @@
+package gather
+
+import "time"
+
+// Backend is the source of the OS-level statistics that gather formats. The
+// default implementation wraps gopsutil; alternatives (a procfs parser, test
+// doubles, a minimal cgo-free build) can be installed with SetBackend.
+// Implementations must be safe for concurrent use.
+type Backend interface {
+	HostInfo() (*HostStat, error)
+	PlatformInformation() (platform, family, version string, err error)
+	Virtualization() (system, role string, err error)
+	CPUInfo() ([]CPUStat, error)
+	CPUCounts(logical bool) (int, error)
+	CPUPercent(interval time.Duration) (float64, error)
+	VirtualMemory() (*MemoryStat, error)
+	SwapMemory() (*MemoryStat, error)
+	DiskUsage(path string) (*UsageStat, error)
+	Connections(kind string) ([]ConnectionStat, error)
+	NetIOCounters() ([]NetIOStat, error)
+	Processes() ([]ProcessStat, error)
+	Temperatures() ([]TemperatureStat, error)
+}
+
+// HostStat describes the running OS; times are in seconds.
+type HostStat struct {
+	Uptime          uint64
+	BootTime        uint64 // Unix timestamp
+	Platform        string
+	PlatformVersion string
+	KernelVersion   string
+	KernelArch      string // uname -m style, e.g. x86_64, aarch64
+}
+
+// CPUStat describes one CPU package as reported by the backend.
+type CPUStat struct {
+	ModelName string
+	Mhz       float64
+}
+
+// MemoryStat is shared by RAM and swap; sizes are in bytes.
+type MemoryStat struct {
+	Total       uint64
+	Used        uint64
+	UsedPercent float64
+}
+
+// UsageStat is filesystem usage in bytes.
+type UsageStat struct {
+	Total       uint64
+	Used        uint64
+	UsedPercent float64
+}
+
+// ConnectionStat is a socket with its endpoints and state (e.g. "LISTEN").
+type ConnectionStat struct {
+	Status     string
+	LocalIP    string
+	LocalPort  uint32
+	RemoteIP   string
+	RemotePort uint32 // 0 for listening and unconnected sockets
+}
+
+// NetIOStat is the traffic of one network interface since boot, in bytes.
+type NetIOStat struct {
+	Name      string
+	BytesSent uint64
+	BytesRecv uint64
+}
+
+// ProcessStat is a running process; CPUTime is user plus system seconds used
+// since it started. Processes that cannot be read (e.g. another user's on
+// some systems) are left out or have zero values.
+type ProcessStat struct {
+	PID     int32
+	Name    string
+	CPUTime float64
+	RSS     uint64 // Resident memory in bytes
+}
+
+// TemperatureStat is one sensor reading in °C.
+type TemperatureStat struct {
+	SensorKey   string
+	Temperature float64
+}
+
+var backend Backend = tracedBackend{defaultBackend()}
+
+// SetBackend replaces the data source used by GetSystemInfo. Call it before
+// gathering; it is not synchronized with running collections.
+func SetBackend(b Backend) {
+	backend = tracedBackend{b}
+}
@@ -0,0 +1,151 @@
+//go:build !nogopsutil
+
+package gather
+
+import (
+	"time"
+
+	"github.com/shirou/gopsutil/v3/cpu"
+	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/shirou/gopsutil/v3/host"
+	"github.com/shirou/gopsutil/v3/mem"
+	psnet "github.com/shirou/gopsutil/v3/net"
+	"github.com/shirou/gopsutil/v3/process"
+)
+
+// gopsutilBackend is the default Backend; it is the only file importing gopsutil.
+type gopsutilBackend struct{}
+
+func defaultBackend() Backend {
+	return gopsutilBackend{}
+}
+
+func (gopsutilBackend) HostInfo() (*HostStat, error) {
+	h, err := host.Info()
+	if err != nil {
+		return nil, err
+	}
+	return &HostStat{
+		Uptime:          h.Uptime,
+		BootTime:        h.BootTime,
+		Platform:        h.Platform,
+		PlatformVersion: h.PlatformVersion,
+		KernelVersion:   h.KernelVersion,
+		KernelArch:      h.KernelArch,
+	}, nil
+}
+
+func (gopsutilBackend) PlatformInformation() (string, string, string, error) {
+	return host.PlatformInformation()
+}
+
+func (gopsutilBackend) Virtualization() (string, string, error) {
+	return host.Virtualization()
+}
+
+func (gopsutilBackend) CPUInfo() ([]CPUStat, error) {
+	infos, err := cpu.Info()
+	if err != nil {
+		return nil, err
+	}
+	stats := make([]CPUStat, 0, len(infos))
+	for _, c := range infos {
+		stats = append(stats, CPUStat{ModelName: c.ModelName, Mhz: c.Mhz})
+	}
+	return stats, nil
+}
+
+func (gopsutilBackend) CPUCounts(logical bool) (int, error) {
+	return cpu.Counts(logical)
+}
+
+func (gopsutilBackend) CPUPercent(interval time.Duration) (float64, error) {
+	percentages, err := cpu.Percent(interval, false)
+	if err != nil {
+		return 0, err
+	}
+	if len(percentages) == 0 {
+		return 0, errUnsupported()
+	}
+	return percentages[0], nil
+}
+
+func (gopsutilBackend) VirtualMemory() (*MemoryStat, error) {
+	v, err := mem.VirtualMemory()
+	if err != nil {
+		return nil, err
+	}
+	return &MemoryStat{Total: v.Total, Used: v.Used, UsedPercent: v.UsedPercent}, nil
+}
+
+func (gopsutilBackend) SwapMemory() (*MemoryStat, error) {
+	s, err := mem.SwapMemory()
+	if err != nil {
+		return nil, err
+	}
+	return &MemoryStat{Total: s.Total, Used: s.Used, UsedPercent: s.UsedPercent}, nil
+}
+
+func (gopsutilBackend) DiskUsage(path string) (*UsageStat, error) {
+	d, err := disk.Usage(path)
+	if err != nil {
+		return nil, err
+	}
+	return &UsageStat{Total: d.Total, Used: d.Used, UsedPercent: d.UsedPercent}, nil
+}
+
+func (gopsutilBackend) Connections(kind string) ([]ConnectionStat, error) {
+	conns, err := psnet.Connections(kind)
+	if err != nil {
+		return nil, err
+	}
+	stats := make([]ConnectionStat, 0, len(conns))
+	for _, c := range conns {
+		stats = append(stats, ConnectionStat{Status: c.Status, LocalIP: c.Laddr.IP, LocalPort: c.Laddr.Port, RemoteIP: c.Raddr.IP, RemotePort: c.Raddr.Port})
+	}
+	return stats, nil
+}
+
+func (gopsutilBackend) NetIOCounters() ([]NetIOStat, error) {
+	counters, err := psnet.IOCounters(true)
+	if err != nil {
+		return nil, err
+	}
+	stats := make([]NetIOStat, 0, len(counters))
+	for _, c := range counters {
+		stats = append(stats, NetIOStat{Name: c.Name, BytesSent: c.BytesSent, BytesRecv: c.BytesRecv})
+	}
+	return stats, nil
+}
+
+func (gopsutilBackend) Processes() ([]ProcessStat, error) {
+	procs, err := process.Processes()
+	if err != nil {
+		return nil, err
+	}
+	stats := make([]ProcessStat, 0, len(procs))
+	for _, p := range procs {
+		name, err := p.Name()
+		if err != nil {
+			continue // Exited since it was listed
+		}
+		stat := ProcessStat{PID: p.Pid, Name: name}
+		if times, err := p.Times(); err == nil {
+			stat.CPUTime = times.User + times.System
+		}
+		if mem, err := p.MemoryInfo(); err == nil {
+			stat.RSS = mem.RSS
+		}
+		stats = append(stats, stat)
+	}
+	return stats, nil
+}
+
+func (gopsutilBackend) Temperatures() ([]TemperatureStat, error) {
+	temps, err := host.SensorsTemperatures()
+	stats := make([]TemperatureStat, 0, len(temps))
+	for _, t := range temps {
+		stats = append(stats, TemperatureStat{SensorKey: t.SensorKey, Temperature: t.Temperature})
+	}
+	return stats, err // gopsutil pairs partial readings with warnings; keep both
+}
@@ -0,0 +1,41 @@
+//go:build nogopsutil
+
+package gather
+
+import "time"
+
+// Built with -tags nogopsutil the gopsutil dependency is dropped entirely; every
+// backend-driven field reports ErrUnsupportedPlatform until SetBackend is called.
+type noBackend struct{}
+
+func defaultBackend() Backend {
+	return noBackend{}
+}
+
+func (noBackend) HostInfo() (*HostStat, error) { return nil, errUnsupported() }
+
+func (noBackend) PlatformInformation() (string, string, string, error) {
+	return "", "", "", errUnsupported()
+}
+
+func (noBackend) Virtualization() (string, string, error) { return "", "", errUnsupported() }
+
+func (noBackend) CPUInfo() ([]CPUStat, error) { return nil, errUnsupported() }
+
+func (noBackend) CPUCounts(bool) (int, error) { return 0, errUnsupported() }
+
+func (noBackend) CPUPercent(time.Duration) (float64, error) { return 0, errUnsupported() }
+
+func (noBackend) VirtualMemory() (*MemoryStat, error) { return nil, errUnsupported() }
+
+func (noBackend) SwapMemory() (*MemoryStat, error) { return nil, errUnsupported() }
+
+func (noBackend) DiskUsage(string) (*UsageStat, error) { return nil, errUnsupported() }
+
+func (noBackend) Connections(string) ([]ConnectionStat, error) { return nil, errUnsupported() }
+
+func (noBackend) NetIOCounters() ([]NetIOStat, error) { return nil, errUnsupported() }
+
+func (noBackend) Processes() ([]ProcessStat, error) { return nil, errUnsupported() }
+
+func (noBackend) Temperatures() ([]TemperatureStat, error) { return nil, errUnsupported() }
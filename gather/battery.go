@@ -0,0 +1,174 @@
+package gather
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// battery is one battery as reported by the OS; zero capacities are unknown.
+type battery struct {
+	charge     int    // Percent
+	status     string // e.g. "charging", "discharging", "full"
+	full       int64  // Capacity when fully charged now, in the source's unit
+	design     int64  // Capacity when new, in the same unit
+	cycles     int64
+	haveCycles bool
+}
+
+// getBattery reports the charge level and, when the firmware exposes design
+// capacity, the battery's health: full capacity now against when it was new,
+// e.g. "85% (discharging), Health: 83%, 412 cycles". It is empty on machines
+// without a battery.
+func getBattery(ctx context.Context) (string, error) {
+	var batteries []battery
+	var err error
+	switch runtime.GOOS {
+	case "linux":
+		batteries, err = linuxBatteries()
+	case "darwin":
+		batteries, err = darwinBatteries(ctx)
+	case "windows":
+		batteries, err = windowsBatteries()
+	default:
+		return "", errUnsupported()
+	}
+	var parts []string
+	for _, b := range batteries {
+		part := fmt.Sprintf("%d%%", b.charge)
+		if b.status != "" {
+			part += " (" + b.status + ")"
+		}
+		if b.full > 0 && b.design > 0 {
+			part += fmt.Sprintf(", Health: %d%%", b.full*100/b.design)
+		}
+		if b.haveCycles {
+			part += fmt.Sprintf(", %d cycles", b.cycles)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "; "), err
+}
+
+// linuxBatteries reads /sys/class/power_supply, skipping the batteries of
+// peripherals (scope "Device") such as wireless mice. Capacities are in µWh
+// (energy_*) or, on some firmware, µAh (charge_*).
+func linuxBatteries() ([]battery, error) {
+	supplies, _ := filepath.Glob("/sys/class/power_supply/*")
+	traceRead("file", "/sys/class/power_supply")
+	var batteries []battery
+	for _, dir := range supplies {
+		if sysfsString(dir, "type") != "Battery" || sysfsString(dir, "scope") == "Device" {
+			continue
+		}
+		charge, err := strconv.Atoi(sysfsString(dir, "capacity"))
+		if err != nil {
+			continue
+		}
+		b := battery{charge: charge, status: strings.ToLower(sysfsString(dir, "status"))}
+		if b.status == "unknown" {
+			b.status = ""
+		}
+		for _, prefix := range []string{"energy", "charge"} {
+			b.full = sysfsInt(dir, prefix+"_full")
+			b.design = sysfsInt(dir, prefix+"_full_design")
+			if b.full > 0 && b.design > 0 {
+				break
+			}
+		}
+		if cycles, err := strconv.ParseInt(sysfsString(dir, "cycle_count"), 10, 64); err == nil && cycles > 0 {
+			b.cycles, b.haveCycles = cycles, true // 0 means the firmware does not count
+		}
+		batteries = append(batteries, b)
+	}
+	return batteries, nil
+}
+
+func sysfsString(dir, name string) string {
+	content, err := os.ReadFile(filepath.Join(dir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(content))
+}
+
+func sysfsInt(dir, name string) int64 {
+	n, _ := strconv.ParseInt(sysfsString(dir, name), 10, 64)
+	return n
+}
+
+// darwinBatteries reads AppleSmartBattery from the IORegistry. On Apple
+// silicon CurrentCapacity and MaxCapacity are percentages and the real full
+// capacity is AppleRawMaxCapacity; Intel Macs report all three in mAh.
+func darwinBatteries(ctx context.Context) ([]battery, error) {
+	objects, err := ioregObjects(ctx, "AppleSmartBattery")
+	if err != nil {
+		return nil, err
+	}
+	var batteries []battery
+	for _, obj := range objects {
+		current, _ := obj["CurrentCapacity"].(int64)
+		maxCapacity, _ := obj["MaxCapacity"].(int64)
+		if maxCapacity <= 0 {
+			continue
+		}
+		b := battery{charge: int(current * 100 / maxCapacity)}
+		switch charging, _ := obj["IsCharging"].(bool); {
+		case charging:
+			b.status = "charging"
+		case obj["FullyCharged"] == true:
+			b.status = "full"
+		case obj["ExternalConnected"] == true:
+			b.status = "not charging"
+		default:
+			b.status = "discharging"
+		}
+		b.full = maxCapacity
+		if raw, ok := obj["AppleRawMaxCapacity"].(int64); ok {
+			b.full = raw
+		}
+		b.design, _ = obj["DesignCapacity"].(int64)
+		b.cycles, b.haveCycles = obj["CycleCount"].(int64)
+		batteries = append(batteries, b)
+	}
+	return batteries, nil
+}
+
+// windowsBatteryStatus names Win32_Battery.BatteryStatus values.
+var windowsBatteryStatus = map[uint16]string{
+	1: "discharging", 2: "on AC", 3: "full", 6: "charging", 7: "charging", 8: "charging", 9: "charging",
+}
+
+// windowsBatteries combines Win32_Battery with the capacities the battery
+// driver publishes in root\WMI, which some drivers leave out.
+func windowsBatteries() ([]battery, error) {
+	var cells []struct {
+		EstimatedChargeRemaining uint16
+		BatteryStatus            uint16
+	}
+	if err := wmiQuery("SELECT EstimatedChargeRemaining, BatteryStatus FROM Win32_Battery", &cells); err != nil {
+		return nil, err
+	}
+	var static []struct{ DesignedCapacity uint32 }
+	var full []struct{ FullChargedCapacity uint32 }
+	var cycles []struct{ CycleCount uint32 }
+	_ = wmiQuery("SELECT DesignedCapacity FROM BatteryStaticData", &static, `root\WMI`)
+	_ = wmiQuery("SELECT FullChargedCapacity FROM BatteryFullChargedCapacity", &full, `root\WMI`)
+	_ = wmiQuery("SELECT CycleCount FROM BatteryCycleCount", &cycles, `root\WMI`)
+	var batteries []battery
+	for i, cell := range cells {
+		b := battery{charge: int(cell.EstimatedChargeRemaining), status: windowsBatteryStatus[cell.BatteryStatus]}
+		if i < len(static) && i < len(full) {
+			b.design, b.full = int64(static[i].DesignedCapacity), int64(full[i].FullChargedCapacity)
+		}
+		if i < len(cycles) && cycles[i].CycleCount > 0 {
+			b.cycles, b.haveCycles = int64(cycles[i].CycleCount), true
+		}
+		batteries = append(batteries, b)
+	}
+	return batteries, nil
+}
@@ -0,0 +1,134 @@
+package gather
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const previousBootLimit = 5
+
+var lastDurationRe = regexp.MustCompile(`\((?:(\d+)\+)?(\d+):(\d+)\)\s*$`)
+
+// getPreviousBoots lists how long the last few boot sessions lasted, newest first.
+func getPreviousBoots(ctx context.Context) (string, error) {
+	var durations []time.Duration
+	var err error
+	switch runtime.GOOS {
+	case "linux":
+		durations, err = journalBootDurations(ctx)
+		if len(durations) == 0 {
+			if wtmp, wtmpErr := wtmpBootDurations(ctx); wtmpErr == nil {
+				durations, err = wtmp, nil
+			}
+		}
+	case "windows":
+		durations, err = eventLogBootDurations(ctx)
+	default:
+		return "", errUnsupported()
+	}
+	if len(durations) == 0 {
+		return "", err
+	}
+	if len(durations) > previousBootLimit {
+		durations = durations[:previousBootLimit]
+	}
+	parts := make([]string, 0, len(durations))
+	for _, d := range durations {
+		parts = append(parts, compactDuration(d))
+	}
+	return strings.Join(parts, ", "), nil
+}
+
+// journalBootDurations reads journalctl --list-boots (JSON needs systemd 251+).
+func journalBootDurations(ctx context.Context) ([]time.Duration, error) {
+	out, err := commandOutput(ctx, "journalctl", "--list-boots", "--no-pager", "-o", "json")
+	if err != nil {
+		return nil, err
+	}
+	var boots []struct {
+		Index      int   `json:"index"`
+		FirstEntry int64 `json:"first_entry"` // microseconds
+		LastEntry  int64 `json:"last_entry"`
+	}
+	if err := json.Unmarshal([]byte(out), &boots); err != nil {
+		return nil, classifyError("journalctl", err)
+	}
+	var durations []time.Duration
+	for i := len(boots) - 1; i >= 0; i-- {
+		if boots[i].Index == 0 {
+			continue // The current boot is already covered by Uptime
+		}
+		durations = append(durations, time.Duration(boots[i].LastEntry-boots[i].FirstEntry)*time.Microsecond)
+	}
+	return durations, nil
+}
+
+// wtmpBootDurations parses `last -x reboot`, whose entries end in "(1+02:03)".
+func wtmpBootDurations(ctx context.Context) ([]time.Duration, error) {
+	out, err := commandOutput(ctx, "last", "-x", "reboot")
+	if err != nil {
+		return nil, err
+	}
+	var durations []time.Duration
+	for _, line := range strings.Split(out, "\n") {
+		if !strings.HasPrefix(line, "reboot") || strings.Contains(line, "still running") {
+			continue
+		}
+		m := lastDurationRe.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		days, _ := strconv.Atoi(m[1])
+		hours, _ := strconv.Atoi(m[2])
+		minutes, _ := strconv.Atoi(m[3])
+		durations = append(durations, time.Duration(days*24+hours)*time.Hour+time.Duration(minutes)*time.Minute)
+	}
+	return durations, nil
+}
+
+// eventLogBootDurations pairs EventLog start (6005) and stop (6006) events.
+func eventLogBootDurations(ctx context.Context) ([]time.Duration, error) {
+	out, err := shellOutput(ctx, "Get-WinEvent -FilterHashtable @{LogName='System'; Id=6005,6006} -MaxEvents 40 | "+
+		"ForEach-Object { \"$($_.Id) $([DateTimeOffset]$_.TimeCreated | ForEach-Object ToUnixTimeSeconds)\" }")
+	if err != nil {
+		return nil, err
+	}
+	// Events arrive newest first: each stop (6006) closes the session opened by the next start (6005)
+	var durations []time.Duration
+	var stop int64
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		ts, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "6006":
+			stop = ts
+		case "6005":
+			if stop > ts {
+				durations = append(durations, time.Duration(stop-ts)*time.Second)
+			}
+			stop = 0
+		}
+	}
+	return durations, nil
+}
+
+func compactDuration(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return strconv.Itoa(int(d.Hours()/24)) + "d"
+	case d >= time.Hour:
+		return strconv.Itoa(int(d.Hours())) + "h"
+	}
+	return strconv.Itoa(int(d.Minutes())) + "m"
+}
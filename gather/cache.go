@@ -0,0 +1,164 @@
+package gather
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// cachedFields are slow to gather but rarely change between runs.
+var cachedFields = map[string]bool{
+	"Packages": true, "Languages": true, "GPU": true, "GraphicsAPI": true, "Compute": true,
+}
+
+// slowRuns is how many runs in a row over the budget get a task skipped.
+const slowRuns = 3
+
+type cacheEntry struct {
+	Value  string    `json:"value"`
+	Stored time.Time `json:"stored"`
+}
+
+// timingEntry holds a task's most recent durations, oldest first.
+type timingEntry struct {
+	Recent []time.Duration `json:"recent"`
+	Stored time.Time       `json:"stored"`
+}
+
+// cacheFile is the on-disk format; Host guards against home directories
+// shared between machines.
+type cacheFile struct {
+	Host    string                 `json:"host"`
+	Entries map[string]cacheEntry  `json:"entries"`
+	Timings map[string]timingEntry `json:"timings,omitempty"`
+}
+
+type resultCache struct {
+	mu         sync.Mutex
+	path       string
+	file       cacheFile
+	dirty      bool
+	ttl        time.Duration
+	refresh    bool
+	slowBudget time.Duration
+}
+
+func cachePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "kernelview", "cache.json")
+}
+
+// loadCache opens the cache for this run, or returns nil when caching is off.
+// A missing or unreadable file just starts an empty cache.
+func loadCache(o *runOptions) *resultCache {
+	if o.cacheTTL <= 0 {
+		return nil
+	}
+	path := cachePath()
+	if path == "" {
+		return nil
+	}
+	host, _ := os.Hostname()
+	c := &resultCache{path: path, ttl: o.cacheTTL, refresh: o.cacheRefresh, slowBudget: o.slowBudget}
+	if content, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(content, &c.file)
+	}
+	if c.file.Host != host || c.file.Entries == nil {
+		c.file = cacheFile{Host: host, Entries: make(map[string]cacheEntry)}
+	}
+	return c
+}
+
+func (c *resultCache) get(field string) (string, bool) {
+	if c == nil || c.refresh || !cachedFields[field] {
+		return "", false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.file.Entries[field]
+	if !ok || time.Since(e.Stored) > c.ttl {
+		return "", false
+	}
+	return e.Value, true
+}
+
+func (c *resultCache) put(field, value string) {
+	if c == nil || !cachedFields[field] {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.file.Entries[field] = cacheEntry{Value: value, Stored: time.Now()}
+	c.dirty = true
+}
+
+// tooSlow reports whether a task went over the slow budget in each of its
+// last runs, the latest of which is within the cache TTL.
+func (c *resultCache) tooSlow(name string) bool {
+	if c == nil || c.slowBudget <= 0 {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.file.Timings[name]
+	if !ok || len(e.Recent) < slowRuns || time.Since(e.Stored) > c.ttl {
+		return false
+	}
+	for _, d := range e.Recent {
+		if d <= c.slowBudget {
+			return false
+		}
+	}
+	return true
+}
+
+// recordTiming remembers how long a task took, keeping the last slowRuns.
+func (c *resultCache) recordTiming(name string, took time.Duration) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.file.Timings == nil {
+		c.file.Timings = make(map[string]timingEntry)
+	}
+	recent := append(c.file.Timings[name].Recent, took)
+	if len(recent) > slowRuns {
+		recent = recent[len(recent)-slowRuns:]
+	}
+	c.file.Timings[name] = timingEntry{Recent: recent, Stored: time.Now()}
+	c.dirty = true
+}
+
+// save writes the cache back if anything changed. It is best-effort: a
+// read-only cache directory only costs speed.
+func (c *resultCache) save() {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	if !c.dirty {
+		c.mu.Unlock()
+		return
+	}
+	content, err := json.Marshal(c.file)
+	c.mu.Unlock()
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, content, 0o644); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		os.Remove(tmp)
+	}
+}
@@ -0,0 +1,56 @@
+package gather
+
+import (
+	"context"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// videoProcessors are V4L2 drivers that process video rather than capture it,
+// such as the Raspberry Pi codec and ISP.
+var videoProcessors = []string{"codec", "isp", "m2m"}
+
+// getCameras names the video capture devices, one per line, e.g. "Integrated
+// Camera: Integrated C". It is empty without a camera.
+func getCameras(ctx context.Context) (string, error) {
+	var names []string
+	var err error
+	switch runtime.GOOS {
+	case "linux":
+		names = linuxCameras()
+	case "darwin":
+		names, err = systemProfilerItems(ctx, "SPCameraDataType")
+	case "windows":
+		var cameras []struct{ Name string }
+		if err = wmiQuery("SELECT Name FROM Win32_PnPEntity WHERE PNPClass = 'Camera' OR PNPClass = 'Image'", &cameras); err == nil {
+			for _, c := range cameras {
+				names = append(names, c.Name)
+			}
+		}
+	default:
+		return "", errUnsupported()
+	}
+	return strings.Join(names, "\n"), err
+}
+
+// linuxCameras reads /sys/class/video4linux. A webcam usually has two nodes,
+// the capture node with index 0 and a metadata node, so only index 0 counts.
+func linuxCameras() []string {
+	traceRead("file", "/sys/class/video4linux")
+	nodes, _ := filepath.Glob("/sys/class/video4linux/video*")
+	var names []string
+	for _, node := range nodes {
+		name := sysfsString(node, "name")
+		if name == "" || sysfsString(node, "index") != "0" || slices.Contains(names, name) {
+			continue
+		}
+		lower := strings.ToLower(name)
+		if slices.ContainsFunc(videoProcessors, func(p string) bool { return strings.Contains(lower, p) }) {
+			continue
+		}
+		names = append(names, name)
+	}
+	return names
+}
@@ -0,0 +1,82 @@
+package gather
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	nvccReleaseRe   = regexp.MustCompile(`release (\d+\.\d+)`)
+	rocmVersionRe   = regexp.MustCompile(`^\d+(\.\d+)+`)
+	cudaPathVersion = regexp.MustCompile(`v?(\d+\.\d+)$`)
+)
+
+// getComputeToolkits lists installed GPU compute stacks (CUDA, ROCm, oneAPI)
+// with their versions. Having none installed is the common case and not an error.
+func getComputeToolkits(ctx context.Context) (string, error) {
+	var toolkits []string
+	if v := getCUDAVersion(ctx); v != "" {
+		toolkits = append(toolkits, "CUDA "+v)
+	}
+	if v := getROCmVersion(ctx); v != "" {
+		toolkits = append(toolkits, "ROCm "+v)
+	}
+	if v := getOneAPIVersion(ctx); v != "" {
+		toolkits = append(toolkits, "oneAPI "+v)
+	}
+	return strings.Join(toolkits, ", "), nil
+}
+
+func getCUDAVersion(ctx context.Context) string {
+	// nvcc is often left off PATH by the distro packages, so try the default prefix too
+	for _, nvcc := range []string{"nvcc", "/usr/local/cuda/bin/nvcc"} {
+		if m := nvccReleaseRe.FindStringSubmatch(runCommand(ctx, nvcc, "--version")); m != nil {
+			return m[1]
+		}
+	}
+	// Windows installers export CUDA_PATH=...\CUDA\v12.4
+	if m := cudaPathVersion.FindStringSubmatch(filepath.Base(os.Getenv("CUDA_PATH"))); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
+func getROCmVersion(ctx context.Context) string {
+	rocm := os.Getenv("ROCM_PATH")
+	if rocm == "" {
+		rocm = "/opt/rocm"
+	}
+	if content, err := readFile(filepath.Join(rocm, ".info", "version")); err == nil {
+		if v := rocmVersionRe.FindString(strings.TrimSpace(string(content))); v != "" {
+			return v // "6.0.2-115" -> "6.0.2"
+		}
+	}
+	// rocminfo talks to the driver, which can stall on a wedged GPU
+	out, err := commandOutputTimeout(ctx, graphicsProbeTimeout, "rocminfo")
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(line), "Runtime Version:"); ok {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
+
+func getOneAPIVersion(ctx context.Context) string {
+	// "Intel(R) oneAPI DPC++/C++ Compiler 2024.0.2 (2024.0.2.20231213)"
+	for _, compiler := range []string{"icpx", "icx"} {
+		out := runCommand(ctx, compiler, "--version")
+		if strings.Contains(out, "oneAPI") {
+			return versionRe.FindString(strings.Split(out, "\n")[0])
+		}
+	}
+	if target, err := filepath.EvalSymlinks("/opt/intel/oneapi/compiler/latest"); err == nil {
+		return filepath.Base(target)
+	}
+	return ""
+}
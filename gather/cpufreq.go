@@ -0,0 +1,68 @@
+package gather
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// readCPUFrequencies fills the current, base and maximum (boost) clock in
+// MHz, where the OS reports them; zero means unknown.
+func readCPUFrequencies(m *Metrics) {
+	switch runtime.GOOS {
+	case "linux":
+		// cpufreq reports kHz; base_frequency exists with intel_pstate and amd-pstate
+		dirs, _ := filepath.Glob("/sys/devices/system/cpu/cpu[0-9]*/cpufreq")
+		traceRead("file", "/sys/devices/system/cpu/cpu*/cpufreq")
+		var sum int64
+		var n int
+		for _, dir := range dirs {
+			if khz := sysfsInt(dir, "scaling_cur_freq"); khz > 0 {
+				sum += khz
+				n++
+			}
+		}
+		if n > 0 {
+			m.CPUCurrentMHz = float64(sum) / float64(n) / 1000
+		}
+		if len(dirs) > 0 {
+			m.CPUBaseMHz = float64(sysfsInt(dirs[0], "base_frequency")) / 1000
+			m.CPUMaxMHz = float64(sysfsInt(dirs[0], "cpuinfo_max_freq")) / 1000
+		}
+	case "darwin":
+		// Intel Macs only; Apple silicon has no frequency sysctls
+		if hz, err := sysctlUint64("hw.cpufrequency"); err == nil {
+			m.CPUBaseMHz = float64(hz) / 1e6
+		}
+		if hz, err := sysctlUint64("hw.cpufrequency_max"); err == nil {
+			m.CPUMaxMHz = float64(hz) / 1e6
+		}
+	case "windows":
+		// MaxClockSpeed is the rated clock despite its name; boost is not reported
+		var processors []struct{ CurrentClockSpeed, MaxClockSpeed uint32 }
+		if err := wmiQuery("SELECT CurrentClockSpeed, MaxClockSpeed FROM Win32_Processor", &processors); err == nil && len(processors) > 0 {
+			m.CPUCurrentMHz = float64(processors[0].CurrentClockSpeed)
+			m.CPUBaseMHz = float64(processors[0].MaxClockSpeed)
+		}
+	}
+}
+
+// formatCPUSpeed shows the rated clock with the current and boost clocks
+// when known, e.g. "2.40 GHz (now 3.61 GHz, max 4.70 GHz)".
+func formatCPUSpeed(m *Metrics) string {
+	rated := m.CPUMHz
+	if m.CPUBaseMHz > 0 {
+		rated = m.CPUBaseMHz
+	}
+	var details []string
+	if m.CPUCurrentMHz > 0 {
+		details = append(details, "now "+formatMHz(m.CPUCurrentMHz))
+	}
+	if m.CPUMaxMHz > rated+1 {
+		details = append(details, "max "+formatMHz(m.CPUMaxMHz))
+	}
+	if len(details) == 0 {
+		return formatMHz(rated)
+	}
+	return formatMHz(rated) + " (" + strings.Join(details, ", ") + ")"
+}
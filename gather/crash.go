@@ -0,0 +1,112 @@
+package gather
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+)
+
+// systemdCoredumps is where systemd-coredump stores dumps; coredumpctl also
+// knows the crashes whose dump was too large to keep or already cleaned up.
+const systemdCoredumps = "/var/lib/systemd/coredump/*"
+
+// crashDumpPatterns lists where each OS leaves kernel panics and application core dumps.
+func crashDumpPatterns() []string {
+	switch runtime.GOOS {
+	case "linux":
+		return []string{
+			"/var/crash/*",   // kdump vmcore directories, apport .crash files
+			systemdCoredumps, // systemd-coredump storage, unless coredumpctl lists it
+			"/var/lib/apport/coredump/*",
+		}
+	case "darwin":
+		home, _ := os.UserHomeDir()
+		return []string{
+			"/Library/Logs/DiagnosticReports/*.panic",
+			"/Library/Logs/DiagnosticReports/Kernel*.ips",
+			filepath.Join(home, "Library/Logs/DiagnosticReports/*.crash"),
+			filepath.Join(home, "Library/Logs/DiagnosticReports/*.ips"),
+		}
+	case "windows":
+		systemRoot := os.Getenv("SystemRoot")
+		if systemRoot == "" {
+			systemRoot = `C:\Windows`
+		}
+		return []string{
+			filepath.Join(systemRoot, "Minidump", "*.dmp"),
+			filepath.Join(systemRoot, "MEMORY.DMP"),
+			filepath.Join(os.Getenv("LOCALAPPDATA"), "CrashDumps", "*.dmp"),
+		}
+	}
+	return nil
+}
+
+// getCrashDumps counts the dumps in crashDumpPatterns. coredumpctl scans the
+// whole journal, which can take seconds, so --fast only globs the dump
+// directories.
+func getCrashDumps(ctx context.Context) (string, error) {
+	patterns := crashDumpPatterns()
+	if patterns == nil {
+		return "", errUnsupported()
+	}
+	count := 0
+	var latest time.Time
+	if !optionsFrom(ctx).fast {
+		if n, last, err := coredumpctlList(ctx); err == nil {
+			count, latest = n, last
+			patterns = slices.DeleteFunc(patterns, func(p string) bool { return p == systemdCoredumps })
+		}
+	}
+	for _, pattern := range patterns {
+		traceRead("file", pattern)
+		matches, _ := filepath.Glob(pattern)
+		for _, match := range matches {
+			fi, err := os.Stat(match)
+			if err != nil {
+				continue
+			}
+			count++
+			if fi.ModTime().After(latest) {
+				latest = fi.ModTime()
+			}
+		}
+	}
+	if count == 0 {
+		return "None", nil
+	}
+	return fmt.Sprintf("%d (latest %s)", count, latest.Format("2006-01-02 15:04")), nil
+}
+
+// coredumpctlList counts the crashes systemd-coredump recorded, whose lines
+// start with the time, e.g. "Tue 2024-05-01 10:22:33 CEST 1234 1000 1000
+// SIGSEGV present /usr/bin/foo 1.2M". It exits 1 when there are none.
+func coredumpctlList(ctx context.Context) (int, time.Time, error) {
+	if runtime.GOOS != "linux" {
+		return 0, time.Time{}, errUnsupported()
+	}
+	if _, err := runner.LookPath("coredumpctl"); err != nil {
+		return 0, time.Time{}, classifyError("coredumpctl", err)
+	}
+	out, err := checkOutput(ctx, []int{1}, "coredumpctl", "list", "--no-legend", "--no-pager")
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	count := 0
+	var latest time.Time
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		count++
+		if t, err := time.ParseInLocation("2006-01-02 15:04:05", fields[1]+" "+fields[2], time.Local); err == nil && t.After(latest) {
+			latest = t
+		}
+	}
+	return count, latest, nil
+}
@@ -0,0 +1,71 @@
+package gather
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+)
+
+func TestCoredumpctlList(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("coredumpctl is Linux-only")
+	}
+	tests := []struct {
+		name   string
+		out    string
+		count  int
+		latest string
+	}{
+		{"none", "", 0, ""},
+		{"two crashes", "Tue 2024-05-01 10:22:33 CEST 1234 1000 1000 SIGSEGV present /usr/bin/foo 1.2M\n" +
+			"Wed 2024-05-08 09:01:02 CEST 4321 1000 1000 SIGABRT missing /usr/bin/bar -\n", 2, "2024-05-08 09:01:02"},
+		{"blank lines skipped", "\n\n", 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useRunner(t, fakeRunner{"coredumpctl list --no-legend --no-pager": tt.out})
+			count, latest, err := coredumpctlList(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if count != tt.count {
+				t.Errorf("count = %d, want %d", count, tt.count)
+			}
+			if got := formatLatest(latest); got != tt.latest {
+				t.Errorf("latest = %q, want %q", got, tt.latest)
+			}
+		})
+	}
+}
+
+func TestCoredumpctlListMissing(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("coredumpctl is Linux-only")
+	}
+	useRunner(t, fakeRunner{})
+	if _, _, err := coredumpctlList(context.Background()); err == nil {
+		t.Error("want an error without coredumpctl")
+	}
+}
+
+func formatLatest(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("2006-01-02 15:04:05")
+}
+
+func TestGetCrashDumpsFastSkipsCoredumpctl(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("coredumpctl is Linux-only")
+	}
+	calls := 0
+	useRunner(t, countingRunner{fakeRunner{"coredumpctl list --no-legend --no-pager": ""}, &calls})
+	if _, err := getCrashDumps(context.WithValue(context.Background(), optionsKey{}, &runOptions{fast: true})); err != nil {
+		t.Fatal(err)
+	}
+	if calls != 0 {
+		t.Errorf("--fast ran coredumpctl %d times", calls)
+	}
+}
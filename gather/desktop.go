@@ -0,0 +1,103 @@
+package gather
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	gnomeVersionRe  = regexp.MustCompile(`\d+(\.\w+)*`)
+	gsettingsItemRe = regexp.MustCompile(`'[^']+'`)
+	dottedVersionRe = regexp.MustCompile(`\d+(\.\d+)+`)
+)
+
+// getGnomeDetail reports the Shell version and how many extensions are enabled.
+func getGnomeDetail(ctx context.Context) string {
+	version := gnomeVersionRe.FindString(runCommand(ctx, "gnome-shell", "--version"))
+	if version == "" {
+		// gnome-shell may not be in PATH (e.g. inside a toolbox); ask the running shell over D-Bus
+		out := runCommand(ctx, "gdbus", "call", "--session", "--dest", "org.gnome.Shell",
+			"--object-path", "/org/gnome/Shell", "--method", "org.freedesktop.DBus.Properties.Get",
+			"org.gnome.Shell", "ShellVersion")
+		version = gnomeVersionRe.FindString(out)
+	}
+
+	extensions := -1
+	if out := runCommand(ctx, "gsettings", "get", "org.gnome.shell", "enabled-extensions"); out != "" {
+		extensions = len(gsettingsItemRe.FindAllString(out, -1))
+	}
+
+	detail := "GNOME"
+	if version != "" {
+		detail += " " + version
+	}
+	if extensions >= 0 {
+		detail += fmt.Sprintf(" (%d extensions)", extensions)
+	}
+	return detail
+}
+
+// getPlasmaDetail reports Plasma, KDE Frameworks and Qt versions.
+func getPlasmaDetail(ctx context.Context) string {
+	var plasma, frameworks, qt string
+
+	// kinfo (Plasma 6.1+) reports all three at once
+	for _, line := range strings.Split(runCommand(ctx, "kinfo"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "KDE Plasma Version:"):
+			plasma = dottedVersionRe.FindString(line)
+		case strings.HasPrefix(line, "KDE Frameworks Version:"):
+			frameworks = dottedVersionRe.FindString(line)
+		case strings.HasPrefix(line, "Qt Version:"):
+			qt = dottedVersionRe.FindString(line)
+		}
+	}
+	if plasma == "" {
+		plasma = dottedVersionRe.FindString(runCommand(ctx, "plasmashell", "--version"))
+	}
+	if frameworks == "" || qt == "" {
+		out := runCommand(ctx, "kf6-config", "--version")
+		if out == "" {
+			out = runCommand(ctx, "kf5-config", "--version")
+		}
+		for _, line := range strings.Split(out, "\n") {
+			if strings.HasPrefix(line, "KDE Frameworks:") && frameworks == "" {
+				frameworks = dottedVersionRe.FindString(line)
+			} else if strings.HasPrefix(line, "Qt:") && qt == "" {
+				qt = dottedVersionRe.FindString(line)
+			}
+		}
+	}
+	if qt == "" {
+		qt = dottedVersionRe.FindString(runCommand(ctx, "qtpaths", "--qt-version"))
+	}
+
+	detail := "KDE Plasma"
+	if plasma != "" {
+		detail += " " + plasma
+	}
+	var extras []string
+	if frameworks != "" {
+		extras = append(extras, "Frameworks "+frameworks)
+	}
+	if qt != "" {
+		extras = append(extras, "Qt "+qt)
+	}
+	if len(extras) > 0 {
+		detail += fmt.Sprintf(" (%s)", strings.Join(extras, ", "))
+	}
+	return detail
+}
+
+func describeDesktop(ctx context.Context, de string) string {
+	lower := strings.ToLower(de)
+	switch {
+	case strings.Contains(lower, "gnome"):
+		return getGnomeDetail(ctx)
+	case strings.Contains(lower, "kde"), strings.Contains(lower, "plasma"):
+		return getPlasmaDetail(ctx)
+	}
+	return ""
+}
@@ -0,0 +1,57 @@
+package gather
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// displayManagers maps login manager programs, which are also their systemd
+// unit names, to display names.
+var displayManagers = []struct{ program, name string }{
+	{"gdm", "GDM"},
+	{"gdm3", "GDM"},
+	{"sddm", "SDDM"},
+	{"lightdm", "LightDM"},
+	{"ly", "Ly"},
+	{"greetd", "greetd"},
+	{"lxdm", "LXDM"},
+	{"xdm", "XDM"},
+	{"slim", "SLiM"},
+	{"cosmic-greeter", "COSMIC Greeter"},
+	{"entrance", "Entrance"},
+	{"nodm", "nodm"},
+}
+
+// getDisplayManager names the login manager: the one systemd starts as
+// display-manager.service or, on other init systems, a running one. Without
+// either, logins go through a text console ("TTY (logind)" when systemd-logind
+// manages the seats).
+func getDisplayManager(ctx context.Context) (string, error) {
+	traceRead("file", "/etc/systemd/system/display-manager.service")
+	if target, err := os.Readlink("/etc/systemd/system/display-manager.service"); err == nil {
+		unit := strings.TrimSuffix(filepath.Base(target), ".service")
+		return displayManagerName(unit), nil
+	}
+	running := runningPrograms()
+	for _, dm := range displayManagers {
+		if slices.Contains(running, dm.program) {
+			return dm.name, nil
+		}
+	}
+	if _, err := os.Stat("/run/systemd/seats"); err == nil {
+		return "TTY (logind)", nil
+	}
+	return "TTY", nil
+}
+
+func displayManagerName(program string) string {
+	for _, dm := range displayManagers {
+		if dm.program == program {
+			return dm.name
+		}
+	}
+	return program
+}
@@ -0,0 +1,177 @@
+package gather
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// drive is one physical storage device.
+type drive struct {
+	model      string
+	size       uint64 // Bytes
+	bus        string // "NVMe", "SATA", "USB", ...
+	rotational bool
+	solidState bool // Known to be solid state; neither flag means unknown
+}
+
+// getDrives lists the physical disks, one per line, e.g. "Samsung SSD 980
+// PRO 1TB (931.5 GB, NVMe SSD)". Unlike Disk it ignores filesystems and
+// partitions.
+func getDrives(ctx context.Context) (string, error) {
+	var drives []drive
+	var err error
+	switch runtime.GOOS {
+	case "linux":
+		drives, err = linuxDrives()
+	case "darwin":
+		drives, err = darwinDrives(ctx)
+	case "windows":
+		drives, err = windowsDrives()
+	default:
+		return "", errUnsupported()
+	}
+	lines := make([]string, 0, len(drives))
+	for _, d := range drives {
+		details := []string{formatBytes(d.size)}
+		kind := d.bus
+		switch {
+		case d.rotational:
+			kind = strings.TrimSpace(kind + " HDD")
+		case d.solidState:
+			kind = strings.TrimSpace(kind + " SSD")
+		}
+		if kind != "" {
+			details = append(details, kind)
+		}
+		lines = append(lines, fmt.Sprintf("%s (%s)", d.model, strings.Join(details, ", ")))
+	}
+	return strings.Join(lines, "\n"), err
+}
+
+// virtualBlockDevices are /sys/block entries that are not hardware.
+var virtualBlockDevices = []string{"loop", "ram", "zram", "dm-", "md", "sr", "nbd", "fd"}
+
+// linuxDrives reads /sys/block; the bus comes from where the device sits in
+// the sysfs device tree, e.g. .../usb2/2-1/.../block/sdb.
+func linuxDrives() ([]drive, error) {
+	traceRead("file", "/sys/block")
+	entries, err := os.ReadDir("/sys/block")
+	if err != nil {
+		return nil, classifyError("/sys/block", err)
+	}
+	var drives []drive
+	for _, entry := range entries {
+		name := entry.Name()
+		if hasAnyPrefix(name, virtualBlockDevices) {
+			continue
+		}
+		dir := filepath.Join("/sys/block", name)
+		sectors := sysfsInt(dir, "size") // Always in 512-byte units
+		if sectors == 0 {
+			continue // Empty card reader slot
+		}
+		d := drive{model: sysfsString(dir, "device/model"), size: uint64(sectors) * 512}
+		if d.model == "" {
+			d.model = name
+		}
+		rotational := sysfsString(dir, "queue/rotational")
+		target, _ := filepath.EvalSymlinks(dir)
+		switch {
+		case strings.HasPrefix(name, "nvme"):
+			d.bus = "NVMe"
+		case strings.HasPrefix(name, "mmcblk"):
+			d.bus = "MMC"
+		case strings.HasPrefix(name, "vd"), strings.Contains(target, "/virtio"):
+			d.bus = "virtio"
+			rotational = "" // Reflects the host's choice, not the disk behind it
+		case strings.Contains(target, "/usb"):
+			d.bus = "USB"
+			rotational = "" // USB bridges often claim rotational for flash drives
+		case strings.Contains(target, "/ata"):
+			d.bus = "SATA"
+		}
+		d.rotational, d.solidState = rotational == "1", rotational == "0"
+		drives = append(drives, d)
+	}
+	return drives, nil
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// darwinDrives asks diskutil about each physical whole disk.
+func darwinDrives(ctx context.Context) ([]drive, error) {
+	out, err := commandOutput(ctx, "diskutil", "list", "-plist", "physical")
+	if err != nil {
+		return nil, err
+	}
+	v, err := parsePlist([]byte(out))
+	if err != nil {
+		return nil, fmt.Errorf("diskutil: %w", err)
+	}
+	list, _ := v.(map[string]any)
+	disks, _ := list["WholeDisks"].([]any)
+	var drives []drive
+	for _, disk := range disks {
+		out, err := commandOutput(ctx, "diskutil", "info", "-plist", plistString(disk))
+		if err != nil {
+			continue
+		}
+		v, err := parsePlist([]byte(out))
+		if err != nil {
+			continue
+		}
+		info, _ := v.(map[string]any)
+		size, _ := info["TotalSize"].(int64)
+		solid, known := info["SolidState"].(bool)
+		drives = append(drives, drive{
+			model:      strings.TrimSpace(plistString(info["MediaName"])),
+			size:       uint64(size),
+			bus:        plistString(info["BusProtocol"]), // "Apple Fabric", "PCI-Express", "USB", "SATA"
+			rotational: known && !solid,
+			solidState: solid,
+		})
+	}
+	return drives, nil
+}
+
+// windowsBusTypes and windowsMediaTypes name MSFT_PhysicalDisk values, the
+// class behind Get-PhysicalDisk.
+var (
+	windowsBusTypes   = map[uint16]string{1: "SCSI", 3: "ATA", 6: "Fibre Channel", 7: "USB", 8: "RAID", 9: "iSCSI", 10: "SAS", 11: "SATA", 12: "SD", 13: "MMC", 15: "Virtual", 17: "NVMe"}
+	windowsMediaTypes = map[uint16]string{3: "HDD", 4: "SSD", 5: "SCM"}
+)
+
+func windowsDrives() ([]drive, error) {
+	var disks []struct {
+		FriendlyName string
+		Size         uint64
+		BusType      uint16
+		MediaType    uint16
+	}
+	if err := wmiQuery("SELECT FriendlyName, Size, BusType, MediaType FROM MSFT_PhysicalDisk", &disks, `root\Microsoft\Windows\Storage`); err != nil {
+		return nil, err
+	}
+	drives := make([]drive, 0, len(disks))
+	for _, d := range disks {
+		media := windowsMediaTypes[d.MediaType]
+		drives = append(drives, drive{
+			model:      d.FriendlyName,
+			size:       d.Size,
+			bus:        windowsBusTypes[d.BusType],
+			rotational: media == "HDD",
+			solidState: media == "SSD" || media == "SCM",
+		})
+	}
+	return drives, nil
+}
@@ -0,0 +1,99 @@
+package gather
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"reflect"
+	"runtime"
+	"sync"
+)
+
+// Error categories for fields that could not be collected (exported for library
+// users). Match them with errors.Is against Result.Err or SystemInfo.Errors.
+var (
+	ErrToolMissing         = errors.New("tool not found")
+	ErrPermission          = errors.New("permission denied")
+	ErrTimeout             = errors.New("timed out")
+	ErrUnsupportedPlatform = errors.New("unsupported platform")
+	ErrCommandDenied       = errors.New("command denied by policy")
+	ErrSkipped             = errors.New("skipped: deadline reached")
+	ErrTooSlow             = errors.New("skipped: repeatedly over the time budget")
+)
+
+// Result pairs a field's value with the reason it could not be collected.
+// An empty Value with a nil Err means the field does not apply to this system
+// (e.g. no battery), as opposed to a failed read.
+type Result struct {
+	Value string
+	Err   error
+}
+
+// Result looks up a SystemInfo field by name (e.g. "GPU") together with its error.
+func (info *SystemInfo) Result(field string) Result {
+	var value string
+	if f := reflect.ValueOf(info).Elem().FieldByName(field); f.IsValid() && f.Kind() == reflect.String {
+		value = f.String()
+	}
+	return Result{Value: value, Err: info.Errors[field]}
+}
+
+// FieldNames lists the SystemInfo value fields (every string field) in
+// declaration order, for use with Result.
+func FieldNames() []string {
+	t := reflect.TypeOf(SystemInfo{})
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Type.Kind() == reflect.String {
+			names = append(names, t.Field(i).Name)
+		}
+	}
+	return names
+}
+
+// classifyError wraps err with the matching category so callers can use errors.Is;
+// subject names what failed, usually the tool or API.
+func classifyError(subject string, err error) error {
+	var exitErr *exec.ExitError
+	var netErr net.Error
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, exec.ErrNotFound):
+		return fmt.Errorf("%s: %w", subject, ErrToolMissing)
+	case errors.Is(err, os.ErrPermission):
+		return fmt.Errorf("%s: %w", subject, ErrPermission)
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return fmt.Errorf("%s: %w", subject, ErrTimeout)
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == 127: // sh: command not found
+		return fmt.Errorf("%s: %w", subject, ErrToolMissing)
+	case errors.As(err, &exitErr) && exitErr.ExitCode() == 126: // sh: not executable
+		return fmt.Errorf("%s: %w", subject, ErrPermission)
+	}
+	return fmt.Errorf("%s: %w", subject, err)
+}
+
+func errUnsupported() error {
+	return fmt.Errorf("%s: %w", runtime.GOOS, ErrUnsupportedPlatform)
+}
+
+// errorSet collects per-field errors from concurrently running gatherers.
+type errorSet struct {
+	mu   sync.Mutex
+	errs map[string]error
+}
+
+func (e *errorSet) record(field string, err error) {
+	if err == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.errs == nil {
+		e.errs = make(map[string]error)
+	}
+	e.errs[field] = err
+}
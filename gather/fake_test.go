@@ -0,0 +1,114 @@
+package gather
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeRunner returns canned output keyed by the command line, e.g.
+// "coredumpctl list --no-legend". Programs without an entry are not found.
+type fakeRunner map[string]string
+
+func (r fakeRunner) Output(ctx context.Context, name string, args ...string) ([]byte, error) {
+	out, ok := r[strings.Join(append([]string{name}, args...), " ")]
+	if !ok {
+		return nil, exec.ErrNotFound
+	}
+	return []byte(out), nil
+}
+
+func (r fakeRunner) LookPath(name string) (string, error) {
+	for command := range r {
+		if program, _, _ := strings.Cut(command, " "); program == name {
+			return "/usr/bin/" + name, nil
+		}
+	}
+	return "", exec.ErrNotFound
+}
+
+// useRunner installs r for the rest of the test.
+func useRunner(tb testing.TB, r Runner) {
+	tb.Helper()
+	saved := runner
+	SetRunner(r)
+	tb.Cleanup(func() { runner = saved })
+}
+
+// fakeBackend is a small, fixed machine.
+type fakeBackend struct{}
+
+func (fakeBackend) HostInfo() (*HostStat, error) {
+	return &HostStat{Uptime: 93784, BootTime: 1700000000, Platform: "debian", PlatformVersion: "12", KernelVersion: "6.1.0-18-amd64", KernelArch: "x86_64"}, nil
+}
+
+func (fakeBackend) PlatformInformation() (string, string, string, error) {
+	return "debian", "debian", "12", nil
+}
+
+func (fakeBackend) Virtualization() (string, string, error) { return "kvm", "guest", nil }
+
+func (fakeBackend) CPUInfo() ([]CPUStat, error) {
+	return []CPUStat{{ModelName: "AMD Ryzen 7 5800X 8-Core Processor", Mhz: 3800}}, nil
+}
+
+func (fakeBackend) CPUCounts(logical bool) (int, error) {
+	if logical {
+		return 16, nil
+	}
+	return 8, nil
+}
+
+func (fakeBackend) CPUPercent(time.Duration) (float64, error) { return 12.5, nil }
+
+func (fakeBackend) VirtualMemory() (*MemoryStat, error) {
+	return &MemoryStat{Total: 32 << 30, Used: 9 << 30, UsedPercent: 28.1}, nil
+}
+
+func (fakeBackend) SwapMemory() (*MemoryStat, error) {
+	return &MemoryStat{Total: 8 << 30, Used: 0, UsedPercent: 0}, nil
+}
+
+func (fakeBackend) DiskUsage(string) (*UsageStat, error) {
+	return &UsageStat{Total: 512 << 30, Used: 200 << 30, UsedPercent: 39.1}, nil
+}
+
+func (fakeBackend) Connections(string) ([]ConnectionStat, error) {
+	return []ConnectionStat{
+		{Status: "LISTEN", LocalIP: "0.0.0.0", LocalPort: 22},
+		{Status: "ESTABLISHED", LocalIP: "10.0.0.2", LocalPort: 22, RemoteIP: "10.0.0.9", RemotePort: 51514},
+	}, nil
+}
+
+func (fakeBackend) NetIOCounters() ([]NetIOStat, error) {
+	return []NetIOStat{{Name: "eth0", BytesSent: 1 << 30, BytesRecv: 4 << 30}}, nil
+}
+
+func (fakeBackend) Processes() ([]ProcessStat, error) {
+	return []ProcessStat{{PID: 1, Name: "systemd", CPUTime: 12, RSS: 12 << 20}, {PID: 812, Name: "sshd", CPUTime: 1, RSS: 8 << 20}}, nil
+}
+
+func (fakeBackend) Temperatures() ([]TemperatureStat, error) {
+	return []TemperatureStat{{SensorKey: "k10temp_tctl", Temperature: 48}}, nil
+}
+
+// useBackend installs b for the rest of the test.
+func useBackend(tb testing.TB, b Backend) {
+	tb.Helper()
+	saved := backend
+	SetBackend(b)
+	tb.Cleanup(func() { backend = saved })
+}
+
+// countingRunner counts the programs started through it.
+type countingRunner struct {
+	fakeRunner
+	calls *int
+}
+
+func (r countingRunner) Output(ctx context.Context, name string, args ...string) ([]byte, error) {
+	*r.calls++
+	return r.fakeRunner.Output(ctx, name, args...)
+}
@@ -0,0 +1,173 @@
+package gather
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// fontconfig prints hintstyle and rgba as their numeric constants.
+var (
+	fcHintStyles = map[string]string{"0": "hintnone", "1": "hintslight", "2": "hintmedium", "3": "hintfull"}
+	fcSubpixels  = map[string]string{"1": "rgb", "2": "bgr", "3": "vrgb", "4": "vbgr", "5": "none"}
+)
+
+// getFontConfig reports the desktop's default font and how fontconfig renders
+// it, e.g. "Cantarell 11 (antialias, hintslight, subpixel rgb)".
+func getFontConfig(ctx context.Context) (string, error) {
+	font := desktopFont(ctx)
+	if font == "" {
+		font = runCommand(ctx, "fc-match", "-f", "%{family[0]}", "sans-serif")
+	}
+	rendering, err := fontRendering(ctx)
+	if font == "" {
+		return "", err
+	}
+	if rendering != "" {
+		font += " (" + rendering + ")"
+	}
+	return font, nil
+}
+
+// desktopFont is the interface font set in the desktop's settings.
+func desktopFont(ctx context.Context) string {
+	desktop := strings.ToLower(os.Getenv("XDG_CURRENT_DESKTOP"))
+	var schema string
+	switch {
+	case strings.Contains(desktop, "kde"):
+		return kdeFont()
+	case strings.Contains(desktop, "cinnamon"):
+		schema = "org.cinnamon.desktop.interface"
+	case strings.Contains(desktop, "mate"):
+		schema = "org.mate.interface"
+	case strings.Contains(desktop, "gnome"), strings.Contains(desktop, "unity"), strings.Contains(desktop, "budgie"), strings.Contains(desktop, "pantheon"):
+		schema = "org.gnome.desktop.interface"
+	}
+	if schema != "" {
+		if font := strings.Trim(runCommand(ctx, "gsettings", "get", schema, "font-name"), "'"); font != "" {
+			return font
+		}
+	}
+	return gtkSetting("gtk-font-name")
+}
+
+// kdeFont reads the font= line of kdeglobals, a QFont string such as
+// "Noto Sans,10,-1,5,50,0,0,0,0,0".
+func kdeFont() string {
+	value := configValue(filepath.Join(configHome(), "kdeglobals"), "General", "font")
+	parts := strings.Split(value, ",")
+	if len(parts) < 2 {
+		return value
+	}
+	return parts[0] + " " + parts[1]
+}
+
+// gtkSetting reads a key of the GTK 3 settings.ini, which other desktops and
+// standalone window managers use through lxappearance or nwg-look.
+func gtkSetting(key string) string {
+	return configValue(filepath.Join(configHome(), "gtk-3.0", "settings.ini"), "Settings", key)
+}
+
+func configHome() string {
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return dir
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config")
+}
+
+// configValue reads key from [section] of an INI-style file.
+func configValue(path, section, key string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	traceRead("file", path)
+	current := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			current = strings.Trim(line, "[]")
+			continue
+		}
+		if k, v, ok := strings.Cut(line, "="); ok && current == section && strings.TrimSpace(k) == key {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
+
+// fontRendering describes fontconfig's antialiasing, hinting and subpixel
+// settings for the default sans-serif font.
+func fontRendering(ctx context.Context) (string, error) {
+	out, err := commandOutput(ctx, "fc-match", "-f", "%{antialias}|%{hintstyle}|%{rgba}", "sans-serif")
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Split(out, "|")
+	if len(fields) != 3 {
+		return "", nil
+	}
+	var parts []string
+	switch fields[0] {
+	case "True":
+		parts = append(parts, "antialias")
+	case "False":
+		parts = append(parts, "no antialias")
+	}
+	if hint, ok := fcHintStyles[fields[1]]; ok {
+		parts = append(parts, hint)
+	}
+	if subpixel, ok := fcSubpixels[fields[2]]; ok {
+		parts = append(parts, "subpixel "+subpixel)
+	}
+	return strings.Join(parts, ", "), nil
+}
+
+// fontExtensions are the font file types counted in the macOS font folders.
+var fontExtensions = []string{".ttf", ".otf", ".ttc", ".otc", ".dfont"}
+
+// getFontCount counts the installed fonts, e.g. "2314 (187 families)" from
+// fontconfig, the font files of the macOS font folders, or the entries of the
+// Windows Fonts registry keys.
+func getFontCount(ctx context.Context) (string, error) {
+	switch runtime.GOOS {
+	case "darwin":
+		home, _ := os.UserHomeDir()
+		n := 0
+		for _, dir := range []string{"/System/Library/Fonts", "/Library/Fonts", filepath.Join(home, "Library/Fonts")} {
+			for _, ext := range fontExtensions {
+				n += countFiles(filepath.Join(dir, "*"+ext))
+			}
+		}
+		return strconv.Itoa(n), nil
+	case "windows":
+		const fontsKey = `SOFTWARE\Microsoft\Windows NT\CurrentVersion\Fonts`
+		system, err := registryValueNames(`HKLM\` + fontsKey)
+		if err != nil {
+			return "", err
+		}
+		user, _ := registryValueNames(`HKCU\` + fontsKey) // Fonts installed for the user only
+		return strconv.Itoa(len(system) + len(user)), nil
+	}
+	out, err := commandOutput(ctx, "fc-list", "--format", "%{family[0]}\n")
+	if err != nil {
+		return "", err
+	}
+	families := make(map[string]bool)
+	n := 0
+	for _, family := range strings.Split(out, "\n") {
+		if family = strings.TrimSpace(family); family != "" {
+			families[family] = true
+			n++
+		}
+	}
+	return fmt.Sprintf("%d (%d families)", n, len(families)), nil
+}
@@ -0,0 +1,30 @@
+package gather
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFontRendering(t *testing.T) {
+	tests := []struct {
+		out  string
+		want string
+	}{
+		{"True|1|1", "antialias, hintslight, subpixel rgb"},
+		{"False|3|5", "no antialias, hintfull, subpixel none"},
+		{"True||", "antialias"},
+		{"garbage", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.out, func(t *testing.T) {
+			useRunner(t, fakeRunner{"fc-match -f %{antialias}|%{hintstyle}|%{rgba} sans-serif": tt.out})
+			got, err := fontRendering(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("fontRendering(context.Background()) = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,201 +1,379 @@
 package gather
 
 import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
 	"fmt"
 	"net"
 	"os"
-	"os/exec"
+	"reflect"
 	"regexp"
 	"runtime"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
-
-	"github.com/shirou/gopsutil/v3/cpu"
-	"github.com/shirou/gopsutil/v3/disk"
-	"github.com/shirou/gopsutil/v3/host"
-	"github.com/shirou/gopsutil/v3/mem"
-	psnet "github.com/shirou/gopsutil/v3/net"
 )
 
 // SystemInfo holds all collected system data. Exported for use in main.
 type SystemInfo struct {
 	OS             string
 	Kernel         string
+	Arch           string
+	KernelModules  string // Only with --verbose
+	KernelTaint    string // Only with --verbose
 	Uptime         string
+	BootTime       string
+	PreviousBoots  string // Only with --verbose
+	CrashDumps     string
+	JournalErrors  string // Opt-in (--journal-errors); errors logged since boot
+	FailedUnits    string // Skipped by --fast; systemd units in the failed state
+	Timezone       string
+	NTPSync        string // Skipped by --fast
 	Shell          string
 	CPU            string
+	Board          string
 	CoresThreads   string
 	CPUSpeed       string
 	CPUUsage       string // Skipped by --fast
 	GPU            string
+	HybridGraphics string // Skipped by --fast; Linux laptops with switchable graphics
+	GraphicsAPI    string // Skipped by --fast
 	RAM            string
+	Battery        string // Charge and health; empty without a battery
+	PowerDraw      string // Skipped by --fast
+	USBDevices     string // Opt-in (--usb); one per line
+	InputDevices   string // One line per kind of device
+	Camera         string // Skipped by --fast; one per line
+	Printers       string // Opt-in (--printers)
 	Disk           string
+	Drives         string // Skipped by --fast; physical disks, one per line
 	Swap           string
 	Hostname       string
+	FQDN           string // Only with --verbose
+	Domain         string // Only with --verbose (Windows)
 	IPAddress      string
+	NetTraffic     string // Primary interface since boot
+	Bandwidth      string // Skipped by --fast; current rates of the primary interface
+	Connections    string // Skipped by --fast; established TCP connections
+	TopCPU         string // Verbose only
+	TopMemory      string // Verbose only
 	OpenPorts      string // Skipped by --fast
 	Locale         string
+	DisplayServer  string
 	Resolution     string
 	WindowManager  string
+	WMPlugins      string
+	Portal         string // xdg-desktop-portal backends (Linux)
+	DisplayManager string // Login manager (Linux)
+	Font           string // Skipped by --fast; default font and fontconfig rendering
+	Wallpaper      string // Skipped by --fast; file name of the desktop wallpaper
+	Autostart      string // Applications started at login
+	ScheduledJobs  string // Opt-in (--scheduled); cron jobs, systemd timers or scheduled tasks
 	DE             string
 	Terminal       string
+	Session        string // "SSH (from ...)" in SSH sessions, which skip the local display fields
 	Packages       string // Skipped by --fast
+	Fonts          string // Skipped by --fast; installed font count
+	LastUpdate     string // Skipped by --fast; last package upgrade
+	PendingUpdates string // Opt-in (--updates); upgradable packages
 	Languages      string // Skipped by --fast
 	Go             string
 	Virtualization string
+	RunningVMs     string // Only with --verbose (hypervisor hosts)
 	Temperature    string // Skipped by --fast
+	SoCTemp        string // Skipped by --fast (device-tree boards)
+	Throttling     string // Skipped by --fast (Raspberry Pi)
+	Editor         string
+	Browser        string // Skipped by --fast
+	Compute        string // Skipped by --fast (CUDA, ROCm, oneAPI)
+
+	Metrics  Metrics                  // The numbers behind Uptime, CPUSpeed, CPUUsage, RAM, Swap, Disk and the temperatures
+	Sources  map[string]Source        // How each field was obtained; only with WithTrace
+	Errors   map[string]error         // Why a field is missing, keyed by field name (see Result)
+	Timings  map[string]time.Duration // How long each gatherer took, keyed by field name or group (HostInfo, CPUInfo, MemoryInfo)
+	Gathered []string                 // The fields this run's modules were started for; others were not selected (--fast, opt-in, ...)
 }
 
+// Patterns and lookup tables are built once and shared by every gather run,
+// so repeated collection in a long-running process doesn't re-allocate them.
+var (
+	prettyNameRe = regexp.MustCompile(`PRETTY_NAME="([^"]+)"`)
+	versionRe    = regexp.MustCompile(`(\d+\.\d+(\.\d+)?)`)
+
+	goArchNames = map[string]string{
+		"amd64": "x86_64", "386": "i686", "arm64": "aarch64", "arm": "arm",
+		"riscv64": "riscv64", "ppc64le": "ppc64le", "ppc64": "ppc64", "s390x": "s390x",
+		"loong64": "loongarch64", "mips64le": "mips64el", "mipsle": "mipsel",
+	}
+	browserProgIDs = map[string]string{
+		"ChromeHTML": "Chrome", "MSEdgeHTM": "Edge", "BraveHTML": "Brave",
+		"OperaStable": "Opera", "VivaldiHTM": "Vivaldi", "IE.HTTP": "Internet Explorer",
+	}
+	languageCommands = [][2]string{
+		{"Go", "go"}, {"Java", "java"}, {"Node", "node"}, {"PHP", "php"},
+		{"Python", "python3"}, {"Ruby", "ruby"}, {"Rust", "rustc"},
+	}
+	// packageCheckers list one package per output line; the count is the
+	// number of non-empty lines after the first header lines are skipped.
+	packageCheckers = map[string]map[string]packageChecker{
+		"linux": {
+			"APT":     {args: []string{"dpkg-query", "-f", "${binary:Package}\\n", "-W"}},
+			"Pacman":  {args: []string{"pacman", "-Qq", "--color", "never"}},
+			"DNF":     {args: []string{"dnf", "list", "installed", "--quiet"}, header: 1},
+			"Flatpak": {args: []string{"flatpak", "list", "--app", "--columns=application"}},
+			"Snap":    {args: []string{"snap", "list"}, header: 1},
+		},
+		"darwin": {
+			"Brew": {args: []string{"brew", "list", "--formula"}},
+			"Cask": {args: []string{"brew", "list", "--cask"}},
+		},
+		"windows": {
+			"Choco":  {args: []string{"choco", "list", "-l", "--limit-output"}},
+			"Winget": {args: []string{"winget", "list"}, header: 2},
+			"Scoop":  {args: []string{"scoop", "list"}, header: 4},
+		},
+	}
+)
+
 // --- Internal Helper Functions ---
 
-func runCommand(name string, arg ...string) string {
-	cmd := exec.Command(name, arg...)
-	cmd.Stderr = nil // Suppress errors
-	out, err := cmd.Output()
-	if err != nil {
-		return ""
+func runCommand(ctx context.Context, name string, arg ...string) string {
+	out, _ := commandOutput(ctx, name, arg...)
+	return out
+}
+
+func runShellCommand(ctx context.Context, command string) string {
+	out, _ := shellOutput(ctx, command)
+	return out
+}
+
+// commandOutput is runCommand for gatherers that report why they failed.
+// Like every command helper it takes the module's ctx, so the program is
+// killed when the run is cancelled or its WithTimeout deadline passes.
+func commandOutput(ctx context.Context, name string, arg ...string) (string, error) {
+	if err := checkCommands(name); err != nil {
+		return "", err
 	}
-	return strings.TrimSpace(string(out))
+	traceRead("command", name)
+	return captureOutput(ctx, name, arg...)
 }
 
-func runShellCommand(command string) string {
-	var cmd *exec.Cmd
+// commandOutputTimeout is commandOutput for tools that can hang, such as ones
+// that open a GPU context; the process is killed once timeout elapses.
+func commandOutputTimeout(ctx context.Context, timeout time.Duration, name string, arg ...string) (string, error) {
+	if err := checkCommands(name); err != nil {
+		return "", err
+	}
+	traceRead("command", name)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	out, err := captureOutput(ctx, name, arg...)
+	if ctx.Err() != nil {
+		return "", classifyError(name, ctx.Err())
+	}
+	return out, err
+}
+
+// shellOutput is runShellCommand for gatherers that report why they failed.
+func shellOutput(ctx context.Context, command string) (string, error) {
+	shell, args := "sh", []string{"-c", command}
 	if runtime.GOOS == "windows" {
-		cmd = exec.Command("powershell", "-NoProfile", "-Command", command)
-	} else {
-		cmd = exec.Command("sh", "-c", command)
+		shell, args = "powershell", []string{"-NoProfile", "-Command", command}
 	}
-	cmd.Stderr = nil // Suppress errors
-	out, err := cmd.Output()
+	programs := append([]string{shell}, scriptPrograms(command)...)
+	if err := checkCommands(programs...); err != nil {
+		return "", err
+	}
+	for _, program := range programs {
+		traceRead("command", program)
+	}
+	return captureOutput(ctx, shell, args...)
+}
+
+// captureOutput runs a program through the Runner, trimming its output.
+func captureOutput(ctx context.Context, name string, arg ...string) (string, error) {
+	var text string
+	err := withOutput(ctx, func(out []byte) { text = string(bytes.TrimSpace(out)) }, name, arg...)
 	if err != nil {
-		return ""
+		return "", classifyError(name, err)
 	}
-	return strings.TrimSpace(string(out))
+	return text, nil
 }
 
 // --- Gathering Functions ---
 
-// Simplified CPU Info - Relies solely on gopsutil
+// Simplified CPU Info - Relies solely on the backend
 func getCPUInfoDetailed() string {
-	if c, err := cpu.Info(); err == nil && len(c) > 0 {
+	if c, err := backend.CPUInfo(); err == nil && len(c) > 0 {
 		return c[0].ModelName
 	}
 	return "Unknown Processor"
 }
 
-func gatherHostInfo(info *SystemInfo, wg *sync.WaitGroup) {
-	defer wg.Done()
-	h, err := host.Info()
+func gatherHostInfo(ctx context.Context, info *SystemInfo, errs *errorSet) {
+	var err error
+	info.Hostname, err = os.Hostname()
+	errs.record("Hostname", err)
+	h, err := backend.HostInfo()
 	if err != nil {
+		err = classifyError("host info", err)
+		for _, field := range []string{"OS", "Kernel", "Uptime", "BootTime"} {
+			errs.record(field, err)
+		}
+		info.Arch = getArch("") // Still known from the build target
 		return
 	}
-	uptimeDuration := time.Second * time.Duration(h.Uptime)
-	days := int(uptimeDuration.Hours() / 24)
-	hours := int(uptimeDuration.Hours()) % 24
-	minutes := int(uptimeDuration.Minutes()) % 60
-	if days > 0 {
-		info.Uptime = fmt.Sprintf("%d days, %d hours", days, hours)
-	} else if hours > 0 {
-		info.Uptime = fmt.Sprintf("%d hours, %d minutes", hours, minutes)
-	} else {
-		info.Uptime = fmt.Sprintf("%d minutes", minutes)
+	info.Metrics.Uptime = time.Second * time.Duration(h.Uptime)
+	info.Uptime = formatUptime(info.Metrics.Uptime)
+	if h.BootTime > 0 {
+		info.BootTime = time.Unix(int64(h.BootTime), 0).Format("2006-01-02 15:04")
 	}
-	info.OS = getOSInfo() // OS info fetched once here
+	info.OS = getOSInfo(ctx) // OS info fetched once here
 	kernelName := h.Platform
 	if kernelName == "windows" {
 		kernelName = "Windows NT"
 	}
 	info.Kernel = fmt.Sprintf("%s %s", strings.Title(kernelName), h.KernelVersion)
-	info.Hostname, _ = os.Hostname()
+	info.Arch = getArch(h.KernelArch)
 }
 
-func gatherCPUInfo(info *SystemInfo, wg *sync.WaitGroup, isFast bool) {
-	defer wg.Done()
+func gatherCPUInfo(ctx context.Context, info *SystemInfo, errs *errorSet) {
 	info.CPU = getCPUInfoDetailed() // Calls the simplified version now
-	if cpuStats, err := cpu.Info(); err == nil && len(cpuStats) > 0 {
-		mhz := cpuStats[0].Mhz
-		if mhz > 1000 {
-			info.CPUSpeed = fmt.Sprintf("%.2f GHz", mhz/1000.0)
-		} else {
-			info.CPUSpeed = fmt.Sprintf("%.0f MHz", mhz)
-		}
+	if cpuStats, err := backend.CPUInfo(); err != nil {
+		errs.record("CPU", classifyError("cpu info", err))
+		errs.record("CPUSpeed", classifyError("cpu info", err))
+	} else if len(cpuStats) > 0 {
+		info.Metrics.CPUMHz = cpuStats[0].Mhz
+		readCPUFrequencies(&info.Metrics)
+		info.CPUSpeed = formatCPUSpeed(&info.Metrics)
 	}
-	cores, _ := cpu.Counts(false) // Physical cores
-	threads, _ := cpu.Counts(true) // Logical cores (threads)
-	info.CoresThreads = fmt.Sprintf("%d/%d", cores, threads)
-
-	if !isFast {
-		percentages, err := cpu.Percent(150*time.Millisecond, false)
-		if err == nil && len(percentages) > 0 {
-			info.CPUUsage = fmt.Sprintf("%.1f%%", percentages[0])
-		} else {
-			info.CPUUsage = "N/A"
-		}
+	cores, _ := backend.CPUCounts(false) // Physical cores
+	threads, _ := backend.CPUCounts(true) // Logical cores (threads)
+	if threads > 0 {
+		info.CoresThreads = fmt.Sprintf("%d/%d", cores, threads)
 	}
+
 }
 
-func gatherMemoryInfo(info *SystemInfo, wg *sync.WaitGroup) {
-	defer wg.Done()
-	v, err := mem.VirtualMemory()
+// gatherCPUUsage samples CPU usage, which takes 150ms.
+func gatherCPUUsage(ctx context.Context, info *SystemInfo, errs *errorSet) {
+	percentage, err := backend.CPUPercent(150 * time.Millisecond)
 	if err == nil {
-		usedGB := float64(v.Used) / (1 << 30)
-		totalGB := float64(v.Total) / (1 << 30)
-		info.RAM = fmt.Sprintf("%.1fGB / %.1fGB (%.0f%%)", usedGB, totalGB, v.UsedPercent)
+		info.Metrics.CPUUsage = &percentage
+		info.CPUUsage = fmt.Sprintf("%.1f%%", percentage)
+	} else {
+		info.CPUUsage = "N/A"
+		errs.record("CPUUsage", classifyError("cpu usage", err))
 	}
-	s, err := mem.SwapMemory()
+}
+
+func gatherMemoryInfo(ctx context.Context, info *SystemInfo, errs *errorSet) {
+	v, err := backend.VirtualMemory()
+	if err != nil {
+		errs.record("RAM", classifyError("memory", err))
+	} else {
+		info.Metrics.RAM = &Usage{Used: v.Used, Total: v.Total, Percent: v.UsedPercent}
+		info.RAM = formatUsage(info.Metrics.RAM, "%.0f")
+	}
+	s, err := backend.SwapMemory()
+	errs.record("Swap", classifyError("swap", err))
 	if err == nil && s.Total > 0 {
-		usedGB := float64(s.Used) / (1 << 30)
-		totalGB := float64(s.Total) / (1 << 30)
-		info.Swap = fmt.Sprintf("%.1fGB / %.1fGB (%.1f%%)", usedGB, totalGB, s.UsedPercent)
+		info.Metrics.Swap = &Usage{Used: s.Used, Total: s.Total, Percent: s.UsedPercent}
+		info.Swap = formatUsage(info.Metrics.Swap, "%.1f")
 	} else {
 		info.Swap = "None"
 	}
 }
 
-func getOSInfo() string {
+func getOSInfo(ctx context.Context) string {
 	switch runtime.GOOS {
 	case "linux":
-		// *** USE os.ReadFile instead of ioutil.ReadFile ***
-		if content, err := os.ReadFile("/etc/os-release"); err == nil {
-			re := regexp.MustCompile(`PRETTY_NAME="([^"]+)"`)
-			if match := re.FindStringSubmatch(string(content)); len(match) > 1 {
+		if content, err := readFile("/etc/os-release"); err == nil {
+			if match := prettyNameRe.FindStringSubmatch(string(content)); len(match) > 1 {
 				return match[1]
 			}
 		}
-		platform, _, version, _ := host.PlatformInformation()
+		platform, _, version, _ := backend.PlatformInformation()
 		if platform != "" && version != "" {
 			return fmt.Sprintf("%s %s", platform, version)
 		}
 	case "windows":
-		productName := runShellCommand("(Get-CimInstance Win32_OperatingSystem).Caption")
-		buildNumber := runShellCommand("(Get-CimInstance Win32_OperatingSystem).BuildNumber")
-		if productName != "" {
-			productName = strings.TrimSpace(strings.Replace(productName, "Microsoft ", "", 1))
-			if buildNumber != "" {
-				return fmt.Sprintf("%s (Build %s)", productName, buildNumber)
-			}
-			return productName
+		if v, err := readRegistry(`HKLM\SOFTWARE\Microsoft\Windows NT\CurrentVersion`,
+			"ProductName", "DisplayVersion", "ReleaseId", "CurrentBuildNumber", "UBR"); err == nil && v["ProductName"] != "" {
+			return windowsVersionName(v)
 		}
 	case "darwin":
-		productVersion := runCommand("sw_vers", "-productVersion")
-		buildVersion := runCommand("sw_vers", "-buildVersion")
+		// kern.osproductversion exists from 10.13.4; older releases need sw_vers
+		productVersion, err := sysctlString("kern.osproductversion")
+		buildVersion, _ := sysctlString("kern.osversion")
+		if err != nil {
+			productVersion = runCommand(ctx, "sw_vers", "-productVersion")
+			buildVersion = runCommand(ctx, "sw_vers", "-buildVersion")
+		}
 		if productVersion != "" {
 			return fmt.Sprintf("macOS %s (%s)", productVersion, buildVersion)
 		}
 	}
-	h, _ := host.Info()
+	h, err := backend.HostInfo()
+	if err != nil {
+		return "Unknown"
+	}
 	return fmt.Sprintf("%s %s", h.Platform, h.PlatformVersion)
 }
 
-func getShell() string {
+// windowsVersionName formats the CurrentVersion registry values as e.g.
+// "Windows 11 Pro 23H2 (Build 22631.3593)".
+func windowsVersionName(v map[string]string) string {
+	name := v["ProductName"]
+	// ProductName was never updated for Windows 11, which starts at build 22000
+	if build, err := strconv.Atoi(v["CurrentBuildNumber"]); err == nil && build >= 22000 {
+		name = strings.Replace(name, "Windows 10", "Windows 11", 1)
+	}
+	release := v["DisplayVersion"] // 20H2 onwards; older builds only have ReleaseId
+	if release == "" {
+		release = v["ReleaseId"]
+	}
+	if release != "" {
+		name += " " + release
+	}
+	build := v["CurrentBuildNumber"]
+	if build == "" {
+		return name
+	}
+	if v["UBR"] != "" {
+		build += "." + v["UBR"]
+	}
+	return fmt.Sprintf("%s (Build %s)", name, build)
+}
+
+// getArch reports the machine architecture in uname terms plus byte order.
+func getArch(kernelArch string) string {
+	if kernelArch == "" {
+		kernelArch = goArchNames[runtime.GOARCH]
+		if kernelArch == "" {
+			kernelArch = runtime.GOARCH
+		}
+	}
+	var probe [2]byte
+	binary.NativeEndian.PutUint16(probe[:], 1)
+	if probe[0] == 1 {
+		return kernelArch + " (little-endian)"
+	}
+	return kernelArch + " (big-endian)"
+}
+
+func getShell(ctx context.Context) (string, error) {
 	shellPath := ""
 	if runtime.GOOS != "windows" {
 		shellPath = os.Getenv("SHELL")
 		if shellPath == "" {
-			return "Unknown"
+			return "Unknown", nil
 		}
 	} else {
 		if os.Getenv("PSModulePath") != "" {
@@ -203,9 +381,9 @@ func getShell() string {
 		} else if os.Getenv("ComSpec") != "" {
 			shellPath = "cmd"
 		} else if os.Getenv("WT_SESSION") != "" {
-			return "Windows Terminal"
+			return "Windows Terminal", nil
 		} else {
-			return "Unknown"
+			return "Unknown", nil
 		}
 	}
 
@@ -216,59 +394,72 @@ func getShell() string {
 	var version string
 	switch shellName {
 	case "bash", "zsh", "fish":
-		out := runCommand(shellPath, "--version")
+		out := runCommand(ctx, shellPath, "--version")
 		if out != "" {
 			firstLine := strings.Split(out, "\n")[0]
-			re := regexp.MustCompile(`(\d+\.\d+(\.\d+)?)`)
-			version = re.FindString(firstLine)
+			version = versionRe.FindString(firstLine)
 		}
 	case "powershell":
-		version = runShellCommand("$PSVersionTable.PSVersion.Major")
+		// The engine key holds the full version, e.g. "5.1.19041.1"
+		if v, err := readRegistry(`HKLM\SOFTWARE\Microsoft\PowerShell\3\PowerShellEngine`, "PowerShellVersion"); err == nil {
+			version, _, _ = strings.Cut(v["PowerShellVersion"], ".")
+		}
 	}
 
 	titleName := strings.Title(shellName)
 	if version != "" {
-		return fmt.Sprintf("%s %s", titleName, version)
+		return fmt.Sprintf("%s %s", titleName, version), nil
 	}
-	return titleName
+	return titleName, nil
 }
 
-func getGPUInfo() string {
+func getGPUInfo(ctx context.Context) (string, error) {
 	switch runtime.GOOS {
 	case "windows":
-		return runShellCommand("(Get-CimInstance Win32_VideoController).Caption")
-	case "linux":
-		output := runShellCommand("lspci -mm | grep -i 'VGA\\|3D\\|Display' | head -n1 | cut -d '\"' -f2,4 | sed 's/\" \"/ /'")
-		if output != "" {
-			return output
+		var controllers []struct{ Caption string }
+		if err := wmiQuery("SELECT Caption FROM Win32_VideoController", &controllers); err != nil {
+			return "", err
+		}
+		var names []string
+		for _, c := range controllers {
+			names = append(names, c.Caption)
 		}
-		output = runShellCommand("lspci | grep -i 'VGA\\|3D\\|Display' | head -n1 | cut -d ':' -f3 | sed 's/ (rev ..)//;s/\\[.*\\]//'")
-		return strings.TrimSpace(output)
+		return strings.Join(names, "\n"), nil
+	case "linux":
+		return getLinuxGPU()
 	case "darwin":
-		output := runShellCommand("system_profiler SPDisplaysDataType | grep 'Chipset Model' | cut -d ':' -f2")
-		return strings.TrimSpace(output)
+		if gpu := getDarwinGPU(ctx); gpu != "" {
+			return gpu, nil
+		}
+		models, err := systemProfilerDisplays(ctx, "Chipset Model")
+		return strings.Join(models, "\n"), err
 	}
-	return "Unknown"
+	return "Unknown", errUnsupported()
 }
 
-func getOpenPorts() string {
-	conns, err := psnet.Connections("tcp")
+// getOpenPorts lists the listening TCP ports, including wildcard binds (the
+// ones reachable from the network), or with WithAllPorts every listening
+// socket.
+func getOpenPorts(ctx context.Context) (string, error) {
+	conns, err := backend.Connections("tcp")
 	if err != nil {
-		return "Unknown"
+		return "Unknown", classifyError("connections", err)
+	}
+	if optionsFrom(ctx).allPorts {
+		return listAllPorts(conns)
 	}
-	portSet := make(map[string]struct{})
+	portSet := make(map[uint32]bool)
 	for _, conn := range conns {
-		if conn.Status == "LISTEN" && conn.Laddr.IP != "::" && conn.Laddr.IP != "0.0.0.0" {
-			portSet[strconv.Itoa(int(conn.Laddr.Port))] = struct{}{}
+		if conn.Status == "LISTEN" {
+			portSet[conn.LocalPort] = true
 		}
 	}
 	if len(portSet) == 0 {
-		return "None"
+		return "None", nil
 	}
 	ports := make([]int, 0, len(portSet))
-	for pStr := range portSet {
-		p, _ := strconv.Atoi(pStr)
-		ports = append(ports, p)
+	for p := range portSet {
+		ports = append(ports, int(p))
 	}
 	sort.Ints(ports)
 	var portStrings []string
@@ -277,127 +468,342 @@ func getOpenPorts() string {
 	}
 	limit := 5
 	if len(portStrings) > limit {
-		return strings.Join(portStrings[:limit], ", ") + "..."
+		return strings.Join(portStrings[:limit], ", ") + "...", nil
 	}
-	return strings.Join(portStrings, ", ")
+	return strings.Join(portStrings, ", "), nil
 }
 
-func getInstalledLanguages() string {
-	langs := []string{"Python", "Go", "Node", "Rust", "Java", "Ruby", "PHP"}
-	cmds := map[string]string{
-		"Python": "python3", "Go": "go", "Node": "node", "Rust": "rustc", "Java": "java",
-		"Ruby": "ruby", "PHP": "php",
+// listAllPorts formats every listening TCP socket and every UDP socket not
+// connected to a peer as address:port/protocol, sorted by port.
+func listAllPorts(tcp []ConnectionStat) (string, error) {
+	udp, err := backend.Connections("udp")
+	if err != nil {
+		return "Unknown", classifyError("connections", err)
+	}
+	type socket struct {
+		addr  string
+		port  uint32
+		proto string
+	}
+	seen := make(map[socket]bool)
+	var sockets []socket
+	add := func(conn ConnectionStat, proto string) {
+		s := socket{net.JoinHostPort(conn.LocalIP, strconv.Itoa(int(conn.LocalPort))), conn.LocalPort, proto}
+		if !seen[s] {
+			seen[s] = true
+			sockets = append(sockets, s)
+		}
 	}
+	for _, conn := range tcp {
+		if conn.Status == "LISTEN" {
+			add(conn, "tcp")
+		}
+	}
+	for _, conn := range udp {
+		if conn.RemotePort == 0 && conn.LocalPort != 0 {
+			add(conn, "udp")
+		}
+	}
+	if len(sockets) == 0 {
+		return "None", nil
+	}
+	sort.Slice(sockets, func(i, j int) bool {
+		a, b := sockets[i], sockets[j]
+		if a.port != b.port {
+			return a.port < b.port
+		}
+		if a.proto != b.proto {
+			return a.proto < b.proto
+		}
+		return a.addr < b.addr
+	})
+	names := make([]string, len(sockets))
+	for i, s := range sockets {
+		names[i] = s.addr + "/" + s.proto
+	}
+	return strings.Join(names, ", "), nil
+}
+
+func getInstalledLanguages(ctx context.Context) (string, error) {
+	// LookPath is a handful of stat calls; not worth a goroutine per language
 	var installed []string
-	var wg sync.WaitGroup
-	mu := &sync.Mutex{}
-	for _, lang := range langs {
-		wg.Add(1)
-		go func(l string) {
-			defer wg.Done()
-			if _, err := exec.LookPath(cmds[l]); err == nil {
-				mu.Lock()
-				installed = append(installed, l)
-				mu.Unlock()
-			}
-		}(lang)
+	for _, lang := range languageCommands { // Already sorted by name
+		if _, err := runner.LookPath(lang[1]); err == nil {
+			installed = append(installed, lang[0])
+		}
 	}
-	wg.Wait()
-	sort.Strings(installed)
 	if len(installed) == 0 {
-		return "None"
+		return "None", nil
 	}
-	return strings.Join(installed, ", ")
+	return strings.Join(installed, ", "), nil
 }
 
-func getIPAddress() string {
+func getIPAddress(ctx context.Context) (string, error) {
+	if ip := routedIP(); ip != nil {
+		return ip.String(), nil
+	}
+	addrs, err := net.InterfaceAddrs()
+	if err == nil {
+		for _, address := range addrs {
+			if ipnet, ok := address.(*net.IPNet); ok && !ipnet.IP.IsLoopback() {
+				if ipnet.IP.To4() != nil {
+					return ipnet.IP.String(), nil
+				}
+			}
+		}
+	}
+	return "127.0.0.1", nil
+}
+
+// routedIP is the local address of the default route, or nil offline. Dialing
+// UDP sends nothing; it only picks the outgoing interface.
+func routedIP() net.IP {
 	conn, err := net.Dial("udp", "8.8.8.8:53")
 	if err != nil {
-		addrs, err := net.InterfaceAddrs()
-		if err == nil {
-			for _, address := range addrs {
-				if ipnet, ok := address.(*net.IPNet); ok && !ipnet.IP.IsLoopback() {
-					if ipnet.IP.To4() != nil {
-						return ipnet.IP.String()
-					}
+		return nil
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).IP
+}
+
+func getFQDN(ctx context.Context) (string, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", err
+	}
+	var fqdn string
+	if runtime.GOOS != "windows" {
+		fqdn = runCommand(ctx, "hostname", "-f")
+	}
+	// Windows has no hostname -f; resolve the name in-process instead
+	if !strings.Contains(fqdn, ".") {
+		fqdn = ""
+		if addrs, err := net.DefaultResolver.LookupHost(ctx, hostname); err == nil {
+			for _, addr := range addrs {
+				names, err := net.DefaultResolver.LookupAddr(ctx, addr)
+				if err == nil && len(names) > 0 && strings.Contains(strings.TrimSuffix(names[0], "."), ".") {
+					fqdn = strings.TrimSuffix(names[0], ".")
+					break
 				}
 			}
 		}
-		return "127.0.0.1"
 	}
-	defer conn.Close()
-	return conn.LocalAddr().(*net.UDPAddr).IP.String()
+	// Only worth showing when it adds a domain part to the plain hostname
+	if fqdn == "" || strings.EqualFold(fqdn, hostname) || !strings.Contains(fqdn, ".") {
+		return "", nil
+	}
+	return fqdn, nil
 }
 
-func getResolution() string {
+func getDomainMembership(ctx context.Context) (string, error) {
+	if runtime.GOOS != "windows" {
+		return "", nil
+	}
+	var systems []struct {
+		PartOfDomain      bool
+		Domain, Workgroup string
+	}
+	if err := wmiQuery("SELECT PartOfDomain, Domain, Workgroup FROM Win32_ComputerSystem", &systems); err != nil {
+		return "", err
+	}
+	if len(systems) == 0 {
+		return "", nil
+	}
+	cs := systems[0]
+	if cs.PartOfDomain && cs.Domain != "" {
+		return fmt.Sprintf("%s (Active Directory)", cs.Domain), nil
+	}
+	if cs.Workgroup != "" {
+		return fmt.Sprintf("%s (Workgroup)", cs.Workgroup), nil
+	}
+	return "", nil
+}
+
+func getResolution(ctx context.Context) (string, error) {
 	switch runtime.GOOS {
 	case "windows":
-		output := runShellCommand("(Get-CimInstance Win32_VideoController).CurrentHorizontalResolution,(Get-CimInstance Win32_VideoController).CurrentVerticalResolution -join 'x'")
-		if output != "" {
-			return output
+		var controllers []struct{ CurrentHorizontalResolution, CurrentVerticalResolution uint32 }
+		if err := wmiQuery("SELECT CurrentHorizontalResolution, CurrentVerticalResolution FROM Win32_VideoController", &controllers); err != nil {
+			return "", err
 		}
+		var modes []string
+		for _, c := range controllers {
+			if c.CurrentHorizontalResolution > 0 { // Inactive adapters report null
+				modes = append(modes, fmt.Sprintf("%dx%d", c.CurrentHorizontalResolution, c.CurrentVerticalResolution))
+			}
+		}
+		return strings.Join(modes, "\n"), nil
 	case "linux":
+		if isHyprland() {
+			if monitors := getHyprlandMonitors(ctx); monitors != "" {
+				return monitors, nil
+			}
+		}
 		if os.Getenv("DISPLAY") != "" {
-			output := runShellCommand("xrandr --current | grep '*' | uniq | awk '{print $1}'")
-			if output != "" {
-				return output
+			out, err := commandOutput(ctx, "xrandr", "--current")
+			// The current mode of each output is marked with '*', e.g. "1920x1080 60.00*+"
+			var modes []string
+			for _, line := range strings.Split(out, "\n") {
+				if f := strings.Fields(line); len(f) > 0 && strings.Contains(line, "*") && !slices.Contains(modes, f[0]) {
+					modes = append(modes, f[0])
+				}
+			}
+			if len(modes) > 0 {
+				return strings.Join(modes, "\n"), nil
+			}
+			if errors.Is(err, ErrToolMissing) && os.Getenv("WAYLAND_DISPLAY") == "" {
+				return "", err
 			}
 		}
-		if os.Getenv("WAYLAND_DISPLAY") != "" {
-			return "Wayland (res?)"
+		return "", nil // Headless or no Wayland query available; see DisplayServer
+	case "darwin":
+		if modes := getDarwinResolution(ctx); modes != "" {
+			return modes, nil
 		}
-		return "Headless"
+		// e.g. "Resolution: 2560 x 1600 Retina"
+		values, err := systemProfilerDisplays(ctx, "Resolution")
+		var modes []string
+		for _, v := range values {
+			if f := strings.Fields(v); len(f) >= 3 {
+				modes = append(modes, f[0]+"x"+f[2])
+			}
+		}
+		return strings.Join(modes, "\n"), err
+	}
+	return "Unknown", errUnsupported()
+}
+
+// getDisplayServer names the graphical session type, or Headless when there is none.
+func getDisplayServer(ctx context.Context) (string, error) {
+	switch runtime.GOOS {
+	case "windows":
+		return "DWM", nil
 	case "darwin":
-		output := runShellCommand("system_profiler SPDisplaysDataType | grep Resolution | awk '{print $2\"x\"$4}'")
-		return strings.TrimSpace(output)
+		return "Quartz", nil
 	}
-	return "Unknown"
+	wayland := os.Getenv("WAYLAND_DISPLAY") != ""
+	x11 := os.Getenv("DISPLAY") != ""
+	switch strings.ToLower(os.Getenv("XDG_SESSION_TYPE")) {
+	case "wayland":
+		wayland = true
+	case "x11":
+		if !wayland {
+			x11 = true
+		}
+	case "mir":
+		return "Mir", nil
+	}
+	switch {
+	case wayland && x11:
+		return "Wayland (XWayland)", nil
+	case wayland:
+		return "Wayland", nil
+	case x11:
+		return "X11", nil
+	}
+	return "Headless", nil
 }
 
-func getTerminal() string {
+// getTerminal names the terminal emulator and, inside tmux, screen or zellij,
+// the multiplexer, e.g. "WezTerm (in tmux 3.4)". tmux replaces TERM_PROGRAM
+// and TERM with its own, so there the outer terminal is usually unknown.
+func getTerminal(ctx context.Context) (string, error) {
+	terminal := "Unknown"
 	termProg := os.Getenv("TERM_PROGRAM")
-	if termProg != "" {
+	term := os.Getenv("TERM")
+	switch {
+	case termProg != "" && termProg != "tmux":
 		termProg = strings.TrimSuffix(termProg, ".app")
 		termProg = strings.Replace(termProg, "iTerm", "iTerm2", 1)
-		return strings.Title(termProg)
+		terminal = strings.Title(termProg)
+	case term != "" && term != "xterm-256color" && !strings.HasPrefix(term, "screen") && !strings.HasPrefix(term, "tmux"):
+		terminal = term
 	}
-	term := os.Getenv("TERM")
-	if term != "" && term != "xterm-256color" && term != "screen" {
-		return term
+	multiplexer := getMultiplexer(ctx)
+	switch {
+	case multiplexer == "":
+		return terminal, nil
+	case terminal == "Unknown":
+		return multiplexer, nil
 	}
-	return "Unknown"
+	return terminal + " (in " + multiplexer + ")", nil
 }
 
-func getWindowManager() string {
+// getMultiplexer reports the terminal multiplexer this runs in, with its
+// version, from the variables each one sets in its panes.
+func getMultiplexer(ctx context.Context) string {
+	switch {
+	case os.Getenv("TMUX") != "":
+		if os.Getenv("TERM_PROGRAM") == "tmux" && os.Getenv("TERM_PROGRAM_VERSION") != "" {
+			return "tmux " + os.Getenv("TERM_PROGRAM_VERSION")
+		}
+		if out := runCommand(ctx, "tmux", "-V"); out != "" {
+			return out // "tmux 3.4"
+		}
+		return "tmux"
+	case os.Getenv("ZELLIJ") != "":
+		if out := runCommand(ctx, "zellij", "--version"); out != "" {
+			return out // "zellij 0.40.1"
+		}
+		return "zellij"
+	case os.Getenv("STY") != "":
+		// "Screen version 4.09.01 (GNU) 20-Aug-23", with exit status 1
+		out, _ := checkOutput(ctx, []int{1}, "screen", "-v")
+		if version := dottedVersionRe.FindString(out); version != "" {
+			return "screen " + version
+		}
+		return "screen"
+	}
+	return ""
+}
+
+func getWindowManager(ctx context.Context) (string, error) {
+	switch runtime.GOOS {
+	case "linux", "windows", "darwin":
+		return windowManagerName(ctx), nil
+	}
+	return "Unknown", errUnsupported()
+}
+
+func windowManagerName(ctx context.Context) string {
 	if runtime.GOOS == "linux" {
+		if isHyprland() {
+			return getHyprlandWM(ctx)
+		}
 		if os.Getenv("WAYLAND_DISPLAY") != "" {
 			session := os.Getenv("XDG_SESSION_TYPE")
 			if session == "wayland" {
 				currentDesktop := os.Getenv("XDG_CURRENT_DESKTOP")
 				switch strings.ToLower(currentDesktop) {
-				case "gnome": return "Mutter (Wayland)"
-				case "kde": return "KWin (Wayland)"
+				case "gnome": return "Mutter"
+				case "kde": return "KWin"
 				case "sway": return "Sway"
 				case "wlroots": return "wlroots based"
 				}
-				return "Wayland"
+				return "Unknown"
 			}
 		}
+		// Ask the X server directly; covers standalone WMs that set no session variables
+		if wm := getEWMHWindowManager(); wm != "" {
+			return wm
+		}
 		desktopSession := os.Getenv("DESKTOP_SESSION")
 		if desktopSession != "" {
 			lowerSession := strings.ToLower(desktopSession)
-			if strings.Contains(lowerSession, "gnome") { return "Mutter (X11)" }
-			if strings.Contains(lowerSession, "kde") || strings.Contains(lowerSession, "plasma") { return "KWin (X11)" }
+			if strings.Contains(lowerSession, "gnome") { return "Mutter" }
+			if strings.Contains(lowerSession, "kde") || strings.Contains(lowerSession, "plasma") { return "KWin" }
 			if strings.Contains(lowerSession, "xfce") { return "Xfwm4" }
 			if strings.Contains(lowerSession, "cinnamon") { return "Muffin" }
 			if strings.Contains(lowerSession, "mate") { return "Marco" }
 			if strings.Contains(lowerSession, "lxqt") { return "Openbox" }
 			return strings.Title(desktopSession)
 		}
-		if wm := runShellCommand("wmctrl -m | grep 'Name:'"); wm != "" {
-			return strings.TrimSpace(strings.Split(wm, ":")[1])
+		for _, line := range strings.Split(runCommand(ctx, "wmctrl", "-m"), "\n") {
+			if name, ok := strings.CutPrefix(line, "Name:"); ok {
+				return strings.TrimSpace(name)
+			}
 		}
-		return "Unknown (X11?)"
+		return "Unknown"
 	} else if runtime.GOOS == "windows" {
 		return "DWM"
 	} else if runtime.GOOS == "darwin" {
@@ -406,70 +812,147 @@ func getWindowManager() string {
 	return "Unknown"
 }
 
-func getSystemLocale() string {
+func getSystemLocale(ctx context.Context) (string, error) {
 	locale := os.Getenv("LANG")
 	if locale == "" {
 		locale = os.Getenv("LC_ALL")
 	}
 	if locale != "" {
-		return strings.Split(locale, ".")[0]
+		return strings.Split(locale, ".")[0], nil
 	}
 	if runtime.GOOS == "windows" {
-		return runShellCommand("(Get-Culture).Name")
+		// The user's display locale, e.g. "en-US"
+		v, err := readRegistry(`HKCU\Control Panel\International`, "LocaleName")
+		if err != nil {
+			return "", err
+		}
+		if v["LocaleName"] != "" {
+			return v["LocaleName"], nil
+		}
 	}
-	return "Unknown"
+	return "Unknown", nil
 }
 
-func getDesktopEnvironment() string {
-	de := os.Getenv("XDG_CURRENT_DESKTOP")
-	if de == "" {
-		de = os.Getenv("DESKTOP_SESSION")
+func getTimezone(ctx context.Context) (string, error) {
+	var zone string
+	switch runtime.GOOS {
+	case "linux", "darwin":
+		zone = os.Getenv("TZ")
+		if zone == "" {
+			if target, err := os.Readlink("/etc/localtime"); err == nil {
+				if i := strings.Index(target, "zoneinfo/"); i >= 0 {
+					zone = target[i+len("zoneinfo/"):]
+				}
+			}
+		}
+		if zone == "" {
+			if content, err := readFile("/etc/timezone"); err == nil {
+				zone = strings.TrimSpace(string(content))
+			}
+		}
+	case "windows":
+		if v, err := readRegistry(`HKLM\SYSTEM\CurrentControlSet\Control\TimeZoneInformation`, "TimeZoneKeyName"); err == nil {
+			zone = v["TimeZoneKeyName"]
+		}
+	}
+	abbr, offset := time.Now().Zone()
+	minutes := (offset % 3600) / 60
+	if minutes < 0 {
+		minutes = -minutes
+	}
+	utc := fmt.Sprintf("UTC%+03d:%02d", offset/3600, minutes)
+	if zone == "" {
+		return fmt.Sprintf("%s (%s)", abbr, utc), nil
 	}
-	de = strings.Replace(de, "plasmawayland", "Plasma (Wayland)", 1)
-	de = strings.Replace(de, "plasma", "Plasma (X11)", 1)
-	return strings.Title(de)
+	return fmt.Sprintf("%s (%s, %s)", zone, abbr, utc), nil
 }
 
-func getPackageCounts() string {
-	var checkers map[string]string
+func getNTPSync(ctx context.Context) (string, error) {
 	switch runtime.GOOS {
 	case "linux":
-		checkers = map[string]string{
-			"APT": "dpkg-query -f . -W | wc -l", "Pacman": "pacman -Qq --color never | wc -l",
-			"DNF": "dnf list installed --quiet | wc -l", "Flatpak": "flatpak list --app --columns=application | wc -l",
-			"Snap": "snap list | tail -n +2 | wc -l",
+		out, err := commandOutput(ctx, "timedatectl", "show", "-p", "NTPSynchronized", "--value")
+		switch out {
+		case "yes":
+			return "Synchronized", nil
+		case "no":
+			return "Not synchronized", nil
 		}
+		return "", err
+	case "windows":
+		out, err := commandOutput(ctx, "w32tm", "/query", "/status")
+		for _, line := range strings.Split(out, "\n") {
+			if strings.HasPrefix(strings.TrimSpace(line), "Source:") {
+				source := strings.TrimSpace(strings.SplitN(line, ":", 2)[1])
+				if source == "" || strings.Contains(source, "Local CMOS Clock") || strings.Contains(source, "Free-running") {
+					return "Not synchronized", nil
+				}
+				return fmt.Sprintf("Synchronized (%s)", source), nil
+			}
+		}
+		return "", err
 	case "darwin":
-		checkers = map[string]string{
-			"Brew": "brew list --formula | wc -l",
-			"Cask": "brew list --cask | wc -l",
+		out, err := commandOutput(ctx, "systemsetup", "-getusingnetworktime")
+		if strings.HasSuffix(out, "On") {
+			return "Synchronized", nil
+		} else if strings.HasSuffix(out, "Off") {
+			return "Not synchronized", nil
 		}
-	case "windows":
-		checkers = map[string]string{
-			"Choco": "(choco list -l | Measure-Object).Count", "Winget": "(winget list | Measure-Object).Count",
-			"Scoop": "(scoop list | Measure-Object).Count",
+		return "", err
+	}
+	return "", errUnsupported()
+}
+
+func getDesktopEnvironment(ctx context.Context) (string, error) {
+	de := os.Getenv("XDG_CURRENT_DESKTOP")
+	if de == "" {
+		de = os.Getenv("DESKTOP_SESSION")
+	}
+	if detail := describeDesktop(ctx, de); detail != "" {
+		return detail, nil
+	}
+	de = strings.Replace(de, "plasmawayland", "Plasma", 1)
+	de = strings.Replace(de, "plasma", "Plasma", 1)
+	return strings.Title(de), nil
+}
+
+type packageChecker struct {
+	args   []string
+	header int // Lines to skip before the package list
+}
+
+func (c packageChecker) count(ctx context.Context) int {
+	out := runCommand(ctx, c.args[0], c.args[1:]...)
+	lines := strings.Split(out, "\n")
+	if len(lines) <= c.header {
+		return 0
+	}
+	n := 0
+	for _, line := range lines[c.header:] {
+		if strings.TrimSpace(line) != "" {
+			n++
 		}
-	default:
-		return "None detected"
+	}
+	return n
+}
+
+func getPackageCounts(ctx context.Context) (string, error) {
+	checkers, ok := packageCheckers[runtime.GOOS]
+	if !ok {
+		return "None detected", errUnsupported()
 	}
 	var wg sync.WaitGroup
 	results := make(chan string, len(checkers))
-	for name, cmd := range checkers {
+	for name, checker := range checkers {
 		wg.Add(1)
-		go func(n, c string) {
+		go func(n string, c packageChecker) {
 			defer wg.Done()
-			baseCmd := strings.Fields(strings.Split(c, "|")[0])[0]
-			if _, err := exec.LookPath(baseCmd); err != nil && baseCmd != "(" {
+			if _, err := runner.LookPath(c.args[0]); err != nil {
 				return
 			}
-			countStr := runShellCommand(c)
-			if countStr != "" {
-				countStr = strings.TrimSpace(countStr)
-				if count, err := strconv.Atoi(countStr); err == nil && count > 0 {
-					results <- fmt.Sprintf("%s (%d)", n, count)
-				}
+			if count := c.count(ctx); count > 0 {
+				results <- fmt.Sprintf("%s (%d)", n, count)
 			}
-		}(name, cmd)
+		}(name, checker)
 	}
 	wg.Wait()
 	close(results)
@@ -479,107 +962,443 @@ func getPackageCounts() string {
 	}
 	sort.Strings(parts)
 	if len(parts) == 0 {
-		return "None detected"
+		return "None detected", nil
 	}
-	return strings.Join(parts, ", ")
+	return strings.Join(parts, ", "), nil
 }
 
-func getDisk() string {
-	d, err := disk.Usage("/")
+func gatherDisk(ctx context.Context, info *SystemInfo, errs *errorSet) {
+	d, err := backend.DiskUsage("/")
 	if err != nil {
-		return "N/A"
+		info.Disk = "N/A"
+		errs.record("Disk", classifyError("disk usage", err))
+		return
+	}
+	info.Metrics.Disk = &Usage{Used: d.Used, Total: d.Total, Percent: d.UsedPercent}
+	info.Disk = formatUsage(info.Metrics.Disk, "%.0f")
+}
+
+// editorProbeTimeout bounds `$EDITOR --version`: GUI editors that ignore the
+// flag open a window instead of exiting.
+const editorProbeTimeout = 2 * time.Second
+
+func getEditor(ctx context.Context) (string, error) {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	fields := strings.Fields(editor) // EDITOR may carry arguments, e.g. "code --wait"
+	if len(fields) == 0 {
+		return "", nil
+	}
+	editorPath := fields[0]
+	editorName := editorPath[strings.LastIndexAny(editorPath, "/\\")+1:]
+	editorName = strings.TrimSuffix(strings.ToLower(editorName), ".exe")
+
+	var version string
+	if _, err := runner.LookPath(editorPath); err == nil {
+		if out, _ := commandOutputTimeout(ctx, editorProbeTimeout, editorPath, "--version"); out != "" {
+			firstLine := strings.Split(out, "\n")[0]
+			version = versionRe.FindString(firstLine)
+		}
+	}
+
+	titleName := strings.Title(editorName)
+	if version != "" {
+		return fmt.Sprintf("%s %s", titleName, version), nil
+	}
+	return titleName, nil
+}
+
+func getDefaultBrowser(ctx context.Context) (string, error) {
+	var id string
+	var err error
+	switch runtime.GOOS {
+	case "linux":
+		id, err = commandOutput(ctx, "xdg-settings", "get", "default-web-browser")
+		if id == "" {
+			id = runCommand(ctx, "xdg-mime", "query", "default", "x-scheme-handler/https")
+		}
+	case "darwin":
+		id = getDarwinBrowserID(ctx)
+		if id == "" {
+			return "Safari", nil // No LaunchServices override means the system default
+		}
+	case "windows":
+		// Same UserChoice ProgId that AssocQueryString resolves for the https scheme
+		var v map[string]string
+		v, err = readRegistry(`HKCU\Software\Microsoft\Windows\Shell\Associations\UrlAssociations\https\UserChoice`, "ProgId")
+		id = v["ProgId"]
+	default:
+		return "", errUnsupported()
+	}
+	if id == "" {
+		return "", err
+	}
+	return prettyBrowserName(id), nil
+}
+
+// prettyBrowserName turns a .desktop file, bundle id or Windows ProgId into a display name.
+func prettyBrowserName(id string) string {
+	if name, ok := browserProgIDs[id]; ok {
+		return name
+	}
+	if strings.HasPrefix(id, "FirefoxURL") {
+		return "Firefox"
+	}
+	id = strings.TrimSuffix(id, ".desktop")
+	if parts := strings.Split(id, "."); len(parts) > 1 {
+		id = parts[len(parts)-1] // Reverse-DNS ids: org.mozilla.firefox, com.apple.safari
+	}
+	return strings.Title(strings.ReplaceAll(id, "-", " "))
+}
+
+func getKernelModules(ctx context.Context) (string, error) {
+	if runtime.GOOS != "linux" {
+		return "", nil
+	}
+	content, err := readFile("/proc/modules")
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil // Kernel built without loadable module support
+	} else if err != nil {
+		return "", classifyError("/proc/modules", err)
+	}
+	notable := []string{"nvidia", "zfs", "kvm", "vboxdrv"}
+	found := make(map[string]bool)
+	count := 0
+	for _, line := range strings.Split(strings.TrimSpace(string(content)), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		count++
+		for _, n := range notable {
+			if fields[0] == n || strings.HasPrefix(fields[0], n+"_") { // kvm_intel, nvidia_drm, ...
+				found[n] = true
+			}
+		}
+	}
+	var highlights []string
+	for _, n := range notable {
+		if found[n] {
+			highlights = append(highlights, n)
+		}
+	}
+	if len(highlights) > 0 {
+		return fmt.Sprintf("%d loaded (%s)", count, strings.Join(highlights, ", ")), nil
 	}
-	usedGB := float64(d.Used) / (1 << 30)
-	totalGB := float64(d.Total) / (1 << 30)
-	return fmt.Sprintf("%.1fGB / %.1fGB (%.0f%%)", usedGB, totalGB, d.UsedPercent)
+	return fmt.Sprintf("%d loaded", count), nil
 }
 
-func getGoVersion() string {
-	return runtime.Version()
+func getGoVersion(ctx context.Context) (string, error) {
+	return runtime.Version(), nil
 }
 
-func getVirtualization() string {
-	virt, _, err := host.Virtualization()
-	if err != nil || virt == "" {
-		return ""
+func getVirtualization(ctx context.Context) (string, error) {
+	virt, role, err := backend.Virtualization()
+	if err != nil {
+		return "", classifyError("virtualization", err)
 	}
-	return virt
+	if virt != "" && role != "" {
+		return fmt.Sprintf("%s (%s)", virt, role), nil
+	}
+	return virt, nil
 }
 
-func getTemperatures() string {
-	temps, err := host.SensorsTemperatures()
-	if err != nil || len(temps) == 0 {
-		return ""
+// getRunningVMs counts guests on a hypervisor host across the common managers.
+func getRunningVMs(ctx context.Context) (string, error) {
+	if _, role, err := backend.Virtualization(); err != nil || role != "host" {
+		return "", nil
+	}
+	counters := map[string]func() (string, error){
+		"libvirt":    func() (string, error) { return commandOutput(ctx, "virsh", "list", "--name") },
+		"VirtualBox": func() (string, error) { return commandOutput(ctx, "VBoxManage", "list", "runningvms") },
 	}
+	if runtime.GOOS == "windows" {
+		counters = map[string]func() (string, error){
+			"Hyper-V": func() (string, error) {
+				// EnabledState 2 is Running; the host itself is also an Msvm_ComputerSystem
+				var vms []struct{ ElementName string }
+				err := wmiQuery("SELECT ElementName FROM Msvm_ComputerSystem WHERE Caption = 'Virtual Machine' AND EnabledState = 2", &vms, `root\virtualization\v2`)
+				var names []string
+				for _, vm := range vms {
+					names = append(names, vm.ElementName)
+				}
+				return strings.Join(names, "\n"), err
+			},
+		}
+	}
+	total := 0
+	var parts []string
+	for name, count := range counters {
+		out, err := count()
+		if err != nil || out == "" {
+			continue
+		}
+		n := len(strings.Split(out, "\n"))
+		total += n
+		parts = append(parts, fmt.Sprintf("%s %d", name, n))
+	}
+	if total == 0 {
+		return "None running", nil
+	}
+	sort.Strings(parts)
+	return fmt.Sprintf("%d running (%s)", total, strings.Join(parts, ", ")), nil
+}
+
+func gatherTemperature(ctx context.Context, info *SystemInfo, errs *errorSet) {
+	temps, err := backend.Temperatures()
+	sensors := sensorReadings(temps)
+	sensors = append(sensors, smartDriveSensors(ctx, sensors)...)
+	sortSensors(sensors)
+	info.Metrics.Sensors = sensors
+	if len(temps) == 0 {
+		// gopsutil returns partial readings alongside warnings, so only fail on no data
+		errs.record("Temperature", classifyError("sensors", err))
+		return
+	}
+	celsius := temps[0].Temperature
 	for _, temp := range temps {
 		lowerKey := strings.ToLower(temp.SensorKey)
 		if strings.Contains(lowerKey, "core") || strings.Contains(lowerKey, "cpu") || strings.Contains(lowerKey, "package") {
-			return fmt.Sprintf("%.1f °C", temp.Temperature)
+			celsius = temp.Temperature
+			break
 		}
 	}
-	return fmt.Sprintf("%.1f °C", temps[0].Temperature)
+	info.Metrics.Temperature = &celsius
+	info.Temperature = formatCelsius(celsius)
 }
 
 // --- Main Orchestration ---
 
-// GetSystemInfo is the main exported function to collect data.
-func GetSystemInfo(isFast bool) *SystemInfo {
-	info := &SystemInfo{}
-	var wg sync.WaitGroup
+// GetSystemInfo is the main exported function to collect data. It returns
+// early, with whatever was collected, when ctx is done or the WithTimeout
+// deadline passes.
+func GetSystemInfo(ctx context.Context, opts ...Option) *SystemInfo {
+	o := newOptions(opts)
+	return collect(ctx, o, selectModules(o), nil)
+}
 
-	// --- Fast Group (Always Run) ---
-	wg.Add(3)
-	go gatherHostInfo(info, &wg)
-	go gatherCPUInfo(info, &wg, isFast)
-	go gatherMemoryInfo(info, &wg)
-
-	// --- Fast Standalone Tasks (Always Run) ---
-	fastTasks := map[string]*string{
-		"Shell": &info.Shell, "GPU": &info.GPU, "Disk": &info.Disk, "IPAddress": &info.IPAddress,
-		"Locale": &info.Locale, "Resolution": &info.Resolution, "WindowManager": &info.WindowManager,
-		"DE": &info.DE, "Terminal": &info.Terminal, "Go": &info.Go,
-		"Virtualization": &info.Virtualization,
-	}
-	fastTaskFuncs := map[string]func() string{
-		"Shell": getShell, "GPU": getGPUInfo, "Disk": getDisk, "IPAddress": getIPAddress,
-		"Locale": getSystemLocale, "Resolution": getResolution, "WindowManager": getWindowManager,
-		"DE": getDesktopEnvironment, "Terminal": getTerminal, "Go": getGoVersion,
-		"Virtualization": getVirtualization,
-	}
-	for key, Ptr := range fastTasks {
-		wg.Add(1)
-		go func(p *string, f func() string) {
-			defer wg.Done()
-			*p = f()
-		}(Ptr, fastTaskFuncs[key])
+// collect runs modules, sending an Update to updates (if not nil) as each
+// one finishes.
+func collect(ctx context.Context, o *runOptions, modules []Module, updates chan<- Update) *SystemInfo {
+	r := &gatherRun{
+		info: &SystemInfo{}, ctx: context.WithValue(ctx, optionsKey{}, o), cache: loadCache(o), updates: updates,
+		pending: make(map[string][]string, len(modules)), fields: make(map[string][]string, len(modules)),
+		finished: make(chan struct{}),
+	}
+	r.remaining.Store(1) // Released once every module has started
+	r.timings.durations = make(map[string]time.Duration, len(modules))
+	if o.jobs > 0 {
+		r.slots = make(chan struct{}, o.jobs)
+	}
+	var trace *runTrace
+	if o.trace {
+		// One job slot for this run, so only one gatherer reads at a time
+		trace = &runTrace{reads: make(map[string][]string), cached: make(map[string]bool)}
+		activeTrace.Store(trace)
+		defer activeTrace.Store(nil)
+		r.slots = make(chan struct{}, 1)
+	}
+	if o.timeout > 0 {
+		var cancel context.CancelFunc
+		r.ctx, cancel = context.WithTimeout(r.ctx, o.timeout)
+		defer cancel()
 	}
 
-	// --- Conditional Slow Tasks (Only run if !isFast) ---
-	if !isFast {
-		slowTasks := map[string]*string{
-			"OpenPorts":   &info.OpenPorts,
-			"Packages":    &info.Packages,
-			"Languages":   &info.Languages,
-			"Temperature": &info.Temperature,
-			// "NetworkSpeed": &info.NetworkSpeed, // REMOVED
+	gathered := make([]string, 0, 2*len(modules))
+	for _, m := range modules {
+		if platforms := m.Platforms(); platforms != nil && !slices.Contains(platforms, runtime.GOOS) {
+			for _, field := range m.Fields() {
+				r.errs.record(field, errUnsupported())
+			}
+			continue
 		}
-		slowTaskFuncs := map[string]func() string{
-			"OpenPorts":   getOpenPorts,
-			"Packages":    getPackageCounts,
-			"Languages":   getInstalledLanguages,
-			"Temperature": getTemperatures,
-			// "NetworkSpeed": getNetworkSpeed, // REMOVED
+		gathered = append(gathered, r.run(m)...)
+	}
+	r.done1()
+
+	r.wait()
+	r.info.Gathered = gathered
+	r.cache.save()
+	r.info.Errors = r.errs.errs
+	if trace != nil {
+		r.mu.Lock()
+		r.info.Sources = trace.sources(r.fields)
+		r.mu.Unlock()
+	}
+	r.info.Timings = r.timings.durations
+	return r.info
+}
+
+// gatherRun is the state of one GetSystemInfo call. Gatherers may outlive it
+// when a timeout is set, so results are committed under mu and dropped once
+// the run is done.
+type gatherRun struct {
+	info    *SystemInfo
+	ctx     context.Context // Done at the deadline
+	errs    errorSet
+	timings timingSet
+	cache   *resultCache
+	slots   chan struct{} // Bounds how many gatherers run at once; nil means unbounded
+	updates chan<- Update // StreamSystemInfo's channel, or nil
+
+	remaining atomic.Int32  // Gatherers still running, plus one until all are started
+	finished  chan struct{} // Closed when remaining drops to zero
+
+	mu      sync.Mutex
+	pending map[string][]string // Running gatherer -> the fields it fills
+	fields  map[string][]string // Every gatherer started -> the fields it fills
+	done    bool
+}
+
+// run starts m on a worker goroutine (see dispatch) and returns its fields.
+func (r *gatherRun) run(m Module) []string {
+	name, fields := m.Name(), m.Fields()
+	r.start(name, fields)
+	dispatch(gatherTask{run: r, module: m, fields: fields})
+	return fields
+}
+
+// scratchInfos recycles the SystemInfo each gatherer fills before it is
+// merged into the result.
+var scratchInfos = sync.Pool{New: func() any { return new(SystemInfo) }}
+
+// gather runs m, recording its fields, errors and how long it took. A fresh
+// cached value is used instead of running a single-field module, and modules
+// that have been too slow are skipped (see WithSlowBudget). m works on a
+// scratch SystemInfo that is merged in when it finishes.
+func (r *gatherRun) gather(m Module, fields []string) {
+	defer r.done1()
+	r.acquire()
+	defer r.release()
+	name := m.Name()
+	traceBegin(name)
+	start := time.Now()
+	scratch := scratchInfos.Get().(*SystemInfo)
+	defer func() {
+		*scratch = SystemInfo{}
+		scratchInfos.Put(scratch)
+	}()
+	var errs map[string]error
+	cached := false
+	if len(fields) == 1 {
+		var value string
+		if value, cached = r.cache.get(fields[0]); cached {
+			setField(scratch, fields[0], value)
+			traceCached(name)
 		}
-		for key, Ptr := range slowTasks {
-			wg.Add(1)
-			go func(p *string, f func() string) {
-				defer wg.Done()
-				*p = f()
-			}(Ptr, slowTaskFuncs[key])
+	}
+	switch {
+	case cached:
+	case r.cache.tooSlow(name):
+		errs = make(map[string]error)
+		for _, field := range fields {
+			errs[field] = fmt.Errorf("%s: %w", name, ErrTooSlow)
+		}
+	default:
+		errs = m.Gather(r.ctx, scratch)
+		r.cache.recordTiming(name, time.Since(start))
+		for _, field := range fields {
+			if errs[field] == nil {
+				r.cache.put(field, getField(scratch, field))
+			}
 		}
 	}
+	r.finish(name, time.Since(start), scratch, errs)
+}
 
-	wg.Wait()
-	return info
+func (r *gatherRun) start(name string, fields []string) {
+	r.remaining.Add(1)
+	r.mu.Lock()
+	r.pending[name] = fields
+	r.fields[name] = fields
+	r.mu.Unlock()
 }
 
+// done1 marks one gatherer (or the starting of them all) as finished.
+func (r *gatherRun) done1() {
+	if r.remaining.Add(-1) == 0 {
+		close(r.finished)
+	}
+}
+
+// finish commits a gatherer's results unless the run has already returned.
+func (r *gatherRun) finish(name string, took time.Duration, scratch *SystemInfo, errs map[string]error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.done {
+		return
+	}
+	delete(r.pending, name)
+	r.timings.record(name, took)
+	mergeInfo(r.info, scratch)
+	for field, err := range errs {
+		r.errs.record(field, err)
+	}
+	if r.updates != nil {
+		r.updates <- r.snapshot(r.fields[name])
+	}
+}
+
+// wait blocks until every gatherer finishes or r.ctx is done, then marks
+// what is still running as skipped.
+func (r *gatherRun) wait() {
+	select {
+	case <-r.finished:
+	case <-r.ctx.Done():
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.done = true
+	for name, fields := range r.pending {
+		for _, field := range fields {
+			r.errs.record(field, fmt.Errorf("%s: %w", name, ErrSkipped))
+		}
+	}
+}
+
+// mergeInfo copies the non-empty string fields and metrics of src into dst.
+func mergeInfo(dst, src *SystemInfo) {
+	d, s := reflect.ValueOf(dst).Elem(), reflect.ValueOf(src).Elem()
+	for i := 0; i < s.NumField(); i++ {
+		if f := s.Field(i); f.Kind() == reflect.String && f.String() != "" {
+			d.Field(i).SetString(f.String())
+		}
+	}
+	dm, sm := reflect.ValueOf(&dst.Metrics).Elem(), reflect.ValueOf(&src.Metrics).Elem()
+	for i := 0; i < sm.NumField(); i++ {
+		if f := sm.Field(i); !f.IsZero() {
+			dm.Field(i).Set(f)
+		}
+	}
+}
+
+// acquire blocks until a job slot is free.
+func (r *gatherRun) acquire() {
+	if r.slots != nil {
+		r.slots <- struct{}{}
+	}
+}
+
+func (r *gatherRun) release() {
+	if r.slots != nil {
+		<-r.slots
+	}
+}
+
+// timingSet collects per-gatherer durations from concurrently running gatherers.
+type timingSet struct {
+	mu        sync.Mutex
+	durations map[string]time.Duration
+}
+
+func (t *timingSet) record(name string, d time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.durations == nil {
+		t.durations = make(map[string]time.Duration)
+	}
+	t.durations[name] = d
+}
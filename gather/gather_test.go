@@ -0,0 +1,108 @@
+package gather
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+// benchmarkCollect runs repeated collections the way a long-running caller
+// would, so allocations per run show up with -benchmem.
+func benchmarkCollect(b *testing.B, opts ...Option) {
+	useBackend(b, fakeBackend{})
+	useRunner(b, fakeRunner{})
+	o := newOptions(opts)
+	modules := selectModules(o)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		collect(context.Background(), o, modules, nil)
+	}
+}
+
+// constantModules are single-field modules that return at once, so
+// collecting them measures the orchestration alone.
+func constantModules() []Module {
+	var modules []Module
+	for _, field := range []string{"Hostname", "OS", "Kernel", "Arch", "Uptime", "Shell", "Terminal", "Locale", "Timezone", "Editor", "Go", "Board"} {
+		modules = append(modules, fast(field, func(context.Context) (string, error) { return "value", nil }))
+	}
+	return modules
+}
+
+// TestCollectAllocs keeps the per-run overhead of collect bounded: a fixed
+// handful for the run itself, plus about one per module, since workers,
+// scratch results and maps are reused.
+func TestCollectAllocs(t *testing.T) {
+	modules := constantModules()
+	o := newOptions(nil)
+	collect(context.Background(), o, modules, nil) // Start the workers
+	allocs := testing.AllocsPerRun(100, func() { collect(context.Background(), o, modules, nil) })
+	if budget := float64(12 + 2*len(modules)); allocs > budget {
+		t.Errorf("collect made %.0f allocations per run for %d modules, want at most %.0f", allocs, len(modules), budget)
+	}
+}
+
+// BenchmarkCollectModules measures the orchestration of many cheap modules.
+func BenchmarkCollectModules(b *testing.B) {
+	o := newOptions(nil)
+	modules := constantModules()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		collect(context.Background(), o, modules, nil)
+	}
+}
+
+// BenchmarkCollectBackend covers the fields that come from the Backend alone.
+func BenchmarkCollectBackend(b *testing.B) {
+	benchmarkCollect(b, WithModules("HostInfo", "CPUInfo", "MemoryInfo", "Disk", "CPUUsage", "Temperature", "OpenPorts", "Connections"))
+}
+
+// BenchmarkCollectFast covers every --fast field; programs are not found, so
+// it measures the file reads and formatting around them.
+func BenchmarkCollectFast(b *testing.B) {
+	benchmarkCollect(b, WithFast())
+}
+
+func TestPackageCheckerCount(t *testing.T) {
+	tests := []struct {
+		name    string
+		checker packageChecker
+		out     string
+		want    int
+	}{
+		{"plain list", packageChecker{args: []string{"pacman", "-Qq"}}, "bash\ncoreutils\nlinux\n", 3},
+		{"blank lines", packageChecker{args: []string{"pacman", "-Qq"}}, "bash\n\n  \nlinux", 2},
+		{"header skipped", packageChecker{args: []string{"winget", "list"}, header: 2}, "Name Id Version\n-----\nGit Git.Git 2.44\n7-Zip 7zip.7zip 23.01\n", 2},
+		{"header only", packageChecker{args: []string{"winget", "list"}, header: 2}, "Name Id Version\n-----", 0},
+		{"no output", packageChecker{args: []string{"pacman", "-Qq"}}, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useRunner(t, fakeRunner{strings.Join(tt.checker.args, " "): tt.out})
+			if got := tt.checker.count(context.Background()); got != tt.want {
+				t.Errorf("count() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEditorProbe(t *testing.T) {
+	tests := []struct {
+		editor string
+		want   string
+		calls  int
+	}{
+		{"nvim", "Nvim 0.9.5", 1},
+		{"code --wait", "Code", 0}, // Not on PATH, so not started
+	}
+	for _, tt := range tests {
+		calls := 0
+		useRunner(t, countingRunner{fakeRunner{"nvim --version": "NVIM v0.9.5\nBuild type: Release"}, &calls})
+		t.Setenv("VISUAL", tt.editor)
+		got, err := getEditor(context.Background())
+		if err != nil || got != tt.want || calls != tt.calls {
+			t.Errorf("getEditor() with VISUAL=%q = %q, %v after %d commands, want %q after %d", tt.editor, got, err, calls, tt.want, tt.calls)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package gather
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// glxinfo and vulkaninfo create a real GPU context, which can stall on a wedged
+// driver, so both run under a deadline.
+const graphicsProbeTimeout = 3 * time.Second
+
+var mesaVersionRe = regexp.MustCompile(`Mesa (\d+(\.\d+)+\S*)`)
+
+// getGraphicsAPI reports the OpenGL version and renderer, the Mesa version when
+// Mesa is the driver, and the Vulkan instance version, e.g.
+// "OpenGL 4.6 (AMD Radeon RX 6800 (radeonsi, navi21)), Mesa 24.0.5, Vulkan 1.3.275".
+func getGraphicsAPI(ctx context.Context) (string, error) {
+	if os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == "" {
+		return "", nil // Headless: there is no GL context to query
+	}
+
+	var parts []string
+	var mesa string
+	glOut, glErr := commandOutputTimeout(ctx, graphicsProbeTimeout, "glxinfo", "-B")
+	if glErr == nil {
+		var renderer, version string
+		for _, line := range strings.Split(glOut, "\n") {
+			key, value, ok := strings.Cut(line, ":")
+			if !ok {
+				continue
+			}
+			switch strings.TrimSpace(key) {
+			case "OpenGL renderer string":
+				renderer = strings.TrimSpace(value)
+			case "OpenGL version string":
+				// "4.6 (Compatibility Profile) Mesa 24.0.5" or "4.6.0 NVIDIA 550.54.14"
+				if fields := strings.Fields(value); len(fields) > 0 {
+					version = fields[0]
+				}
+				if m := mesaVersionRe.FindStringSubmatch(value); m != nil {
+					mesa = m[1]
+				}
+			}
+		}
+		if version != "" {
+			gl := "OpenGL " + version
+			if renderer != "" {
+				gl += fmt.Sprintf(" (%s)", renderer)
+			}
+			parts = append(parts, gl)
+		}
+	}
+
+	vkOut, vkErr := commandOutputTimeout(ctx, graphicsProbeTimeout, "vulkaninfo", "--summary")
+	var vulkan string
+	if vkErr == nil {
+		for _, line := range strings.Split(vkOut, "\n") {
+			line = strings.TrimSpace(line)
+			if v, ok := strings.CutPrefix(line, "Vulkan Instance Version:"); ok {
+				vulkan = strings.TrimSpace(v)
+			} else if mesa == "" && strings.HasPrefix(line, "driverInfo") {
+				if m := mesaVersionRe.FindStringSubmatch(line); m != nil {
+					mesa = m[1]
+				}
+			}
+		}
+	}
+
+	if mesa != "" {
+		parts = append(parts, "Mesa "+mesa)
+	}
+	if vulkan != "" {
+		parts = append(parts, "Vulkan "+vulkan)
+	}
+	if len(parts) == 0 {
+		// Prefer the GL error: glxinfo is the more commonly installed of the two
+		if glErr != nil {
+			return "", glErr
+		}
+		if vkErr != nil {
+			return "", vkErr
+		}
+		return "", errors.New("graphics: no OpenGL or Vulkan information reported")
+	}
+	return strings.Join(parts, ", "), nil
+}
@@ -0,0 +1,117 @@
+package gather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+type hyprMonitor struct {
+	Name        string  `json:"name"`
+	Width       int     `json:"width"`
+	Height      int     `json:"height"`
+	RefreshRate float64 `json:"refreshRate"`
+	X           int     `json:"x"`
+	Y           int     `json:"y"`
+}
+
+func isHyprland() bool {
+	return os.Getenv("HYPRLAND_INSTANCE_SIGNATURE") != ""
+}
+
+// hyprQuery asks Hyprland for JSON over its IPC socket, falling back to hyprctl.
+func hyprQuery(ctx context.Context, command string, v interface{}) error {
+	out := hyprSocketRequest("j/" + command)
+	if out == "" {
+		args := append(strings.Fields(command), "-j")
+		out = runCommand(ctx, "hyprctl", args...)
+	}
+	if out == "" {
+		return fmt.Errorf("hyprland: no response to %q", command)
+	}
+	return json.Unmarshal([]byte(out), v)
+}
+
+func hyprSocketRequest(request string) string {
+	signature := os.Getenv("HYPRLAND_INSTANCE_SIGNATURE")
+	// Hyprland >= 0.40 moved its sockets from /tmp/hypr to $XDG_RUNTIME_DIR/hypr
+	candidates := []string{filepath.Join("/tmp/hypr", signature, ".socket.sock")}
+	if runtimeDir := os.Getenv("XDG_RUNTIME_DIR"); runtimeDir != "" {
+		candidates = append([]string{filepath.Join(runtimeDir, "hypr", signature, ".socket.sock")}, candidates...)
+	}
+	for _, path := range candidates {
+		conn, err := net.DialTimeout("unix", path, 500*time.Millisecond)
+		if err != nil {
+			continue
+		}
+		_ = conn.SetDeadline(time.Now().Add(time.Second))
+		_, err = conn.Write([]byte(request))
+		var out []byte
+		if err == nil {
+			out, err = io.ReadAll(conn)
+		}
+		conn.Close()
+		if err == nil {
+			return strings.TrimSpace(string(out))
+		}
+	}
+	return ""
+}
+
+func getHyprlandWM(ctx context.Context) string {
+	var version struct {
+		Tag string `json:"tag"`
+	}
+	if err := hyprQuery(ctx, "version", &version); err != nil || version.Tag == "" {
+		return "Hyprland"
+	}
+	return fmt.Sprintf("Hyprland %s", strings.TrimPrefix(version.Tag, "v"))
+}
+
+// getHyprlandMonitors lists monitors left-to-right as laid out in the compositor.
+func getHyprlandMonitors(ctx context.Context) string {
+	var monitors []hyprMonitor
+	if err := hyprQuery(ctx, "monitors", &monitors); err != nil || len(monitors) == 0 {
+		return ""
+	}
+	sort.Slice(monitors, func(i, j int) bool {
+		if monitors[i].X != monitors[j].X {
+			return monitors[i].X < monitors[j].X
+		}
+		return monitors[i].Y < monitors[j].Y
+	})
+	var parts []string
+	for _, m := range monitors {
+		parts = append(parts, fmt.Sprintf("%dx%d@%.0fHz (%s)", m.Width, m.Height, m.RefreshRate, m.Name))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func getHyprlandPlugins(ctx context.Context) string {
+	var plugins []struct {
+		Name string `json:"name"`
+	}
+	if err := hyprQuery(ctx, "plugin list", &plugins); err != nil || len(plugins) == 0 {
+		return ""
+	}
+	var names []string
+	for _, p := range plugins {
+		names = append(names, p.Name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func getWMPlugins(ctx context.Context) (string, error) {
+	if isHyprland() {
+		return getHyprlandPlugins(ctx), nil
+	}
+	return "", nil
+}
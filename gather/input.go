@@ -0,0 +1,169 @@
+package gather
+
+import (
+	"context"
+	"errors"
+	"math/bits"
+	"os"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Input event and property bits from linux/input-event-codes.h.
+const (
+	evRel           = 0x02
+	evAbs           = 0x03
+	evRep           = 0x14
+	btnTouch        = 0x14a
+	inputPropPtr    = 0 // INPUT_PROP_POINTER: needs a pointer, as touchpads do
+	inputPropDirect = 1 // INPUT_PROP_DIRECT: touches the screen itself
+)
+
+// inputKinds are the device kinds in display order.
+var inputKinds = []string{"Keyboard", "Mouse", "Touchpad", "Touchscreen"}
+
+// getInputDevices names the keyboards, mice and touchpads by kind, one kind
+// per line, and says whether there is a touchscreen, e.g. "Keyboard: AT
+// Translated Set 2 keyboard" … "Touchscreen: none". Without a keyboard, mouse
+// or touchpad the value is empty.
+func getInputDevices(ctx context.Context) (string, error) {
+	var devices map[string][]string
+	var err error
+	switch runtime.GOOS {
+	case "linux":
+		devices, err = linuxInputDevices()
+	case "windows":
+		devices, err = windowsInputDevices()
+	default:
+		return "", errUnsupported()
+	}
+	if devices == nil {
+		return "", err
+	}
+	var lines []string
+	for _, kind := range inputKinds {
+		names := devices[kind]
+		switch {
+		case len(names) > 0:
+			lines = append(lines, kind+": "+strings.Join(names, ", "))
+		case kind == "Touchscreen" && len(lines) > 0:
+			lines = append(lines, "Touchscreen: none")
+		}
+	}
+	return strings.Join(lines, "\n"), nil // Empty on headless machines
+}
+
+// linuxInputDevices classifies /proc/bus/input/devices much like udev's
+// input_id builtin. Each device is a block of lines such as:
+//
+//	N: Name="SynPS/2 Synaptics TouchPad"
+//	H: Handlers=mouse0 event5
+//	B: PROP=5
+//	B: EV=b
+//	B: KEY=e520 10000 0 0 0 0
+//	B: ABS=660800011000003
+func linuxInputDevices() (map[string][]string, error) {
+	content, err := readFile("/proc/bus/input/devices")
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil // No input subsystem, as in containers
+	} else if err != nil {
+		return nil, classifyError("/proc/bus/input/devices", err)
+	}
+	return parseInputDevices(string(content)), nil
+}
+
+func parseInputDevices(content string) map[string][]string {
+	devices := make(map[string][]string)
+	for _, block := range strings.Split(content, "\n\n") {
+		var name string
+		var handlers []string
+		bitmaps := make(map[string]string)
+		for _, line := range strings.Split(block, "\n") {
+			key, value, _ := strings.Cut(line, ": ")
+			switch key {
+			case "N":
+				name = strings.Trim(strings.TrimPrefix(value, "Name="), `"`)
+			case "H":
+				handlers = strings.Fields(strings.TrimPrefix(value, "Handlers="))
+			case "B":
+				if k, v, ok := strings.Cut(value, "="); ok {
+					bitmaps[k] = v
+				}
+			}
+		}
+		kind := ""
+		hasBit := func(bitmap string, bit int) bool { return inputBit(bitmaps[bitmap], bit) }
+		switch {
+		case hasBit("PROP", inputPropDirect) && hasBit("EV", evAbs) && hasBit("KEY", btnTouch):
+			kind = "Touchscreen"
+		case hasBit("PROP", inputPropPtr) && hasBit("KEY", btnTouch), strings.Contains(strings.ToLower(name), "touchpad"):
+			kind = "Touchpad"
+		case hasHandler(handlers, "mouse") && hasBit("EV", evRel):
+			kind = "Mouse"
+		case hasHandler(handlers, "kbd") && hasBit("EV", evRep):
+			kind = "Keyboard" // Key repeat sets keyboards apart from power buttons and media keys
+		}
+		if kind != "" && name != "" && !slices.Contains(devices[kind], name) {
+			devices[kind] = append(devices[kind], name)
+		}
+	}
+	return devices
+}
+
+func hasHandler(handlers []string, prefix string) bool {
+	return slices.ContainsFunc(handlers, func(h string) bool { return strings.HasPrefix(h, prefix) })
+}
+
+// inputBit tests a bit of a /proc/bus/input bitmap: hex words of the kernel's
+// long size, most significant first.
+func inputBit(bitmap string, bit int) bool {
+	words := strings.Fields(bitmap)
+	i := len(words) - 1 - bit/bits.UintSize
+	if i < 0 {
+		return false
+	}
+	word, err := strconv.ParseUint(words[i], 16, 64)
+	return err == nil && word&(1<<(bit%bits.UintSize)) != 0
+}
+
+// windowsPointingTypes maps Win32_PointingDevice.PointingType to a kind;
+// trackballs and the like count as mice.
+var windowsPointingTypes = map[uint16]string{3: "Mouse", 4: "Mouse", 5: "Mouse", 6: "Touchpad", 7: "Touchpad", 8: "Touchscreen", 9: "Mouse"}
+
+// windowsInputDevices asks WMI for keyboards and pointing devices; touch
+// screens are HID devices that only show up among the Plug and Play entities.
+func windowsInputDevices() (map[string][]string, error) {
+	var keyboards []struct{ Name string }
+	if err := wmiQuery("SELECT Name FROM Win32_Keyboard", &keyboards); err != nil {
+		return nil, err
+	}
+	devices := make(map[string][]string)
+	add := func(kind, name string) {
+		if name != "" && !slices.Contains(devices[kind], name) {
+			devices[kind] = append(devices[kind], name)
+		}
+	}
+	for _, k := range keyboards {
+		add("Keyboard", k.Name)
+	}
+	var pointers []struct {
+		Name         string
+		PointingType uint16
+	}
+	_ = wmiQuery("SELECT Name, PointingType FROM Win32_PointingDevice", &pointers)
+	for _, p := range pointers {
+		kind := windowsPointingTypes[p.PointingType]
+		if kind == "" {
+			kind = "Mouse"
+		}
+		add(kind, p.Name)
+	}
+	var touch []struct{ Name string }
+	_ = wmiQuery("SELECT Name FROM Win32_PnPEntity WHERE Name LIKE '%touch screen%'", &touch)
+	for _, t := range touch {
+		add("Touchscreen", t.Name)
+	}
+	return devices, nil
+}
@@ -0,0 +1,100 @@
+package gather
+
+import (
+	"math/bits"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestInputBit(t *testing.T) {
+	// On 64-bit kernels each word is 64 bits, most significant word first.
+	high := "1 0"
+	if bits.UintSize == 32 {
+		high = "1 0 0"
+	}
+	tests := []struct {
+		bitmap string
+		bit    int
+		want   bool
+	}{
+		{"b", 0, true},
+		{"b", 2, false},
+		{"b", 3, true},
+		{"120013", evRep, true},
+		{"", 0, false},
+		{high, 64, true},
+		{high, 0, false},
+		{"zz", 0, false},
+	}
+	for _, tt := range tests {
+		if got := inputBit(tt.bitmap, tt.bit); got != tt.want {
+			t.Errorf("inputBit(%q, %d) = %v, want %v", tt.bitmap, tt.bit, got, tt.want)
+		}
+	}
+}
+
+const testInputDevices = `I: Bus=0011 Vendor=0001 Product=0001 Version=ab83
+N: Name="AT Translated Set 2 keyboard"
+H: Handlers=sysrq kbd event0 leds
+B: PROP=0
+B: EV=120013
+B: KEY=402000000 3803078f800d001 feffffdfffefffff fffffffffffffffe
+
+I: Bus=0019 Vendor=0000 Product=0001 Version=0000
+N: Name="Power Button"
+H: Handlers=kbd event1
+B: PROP=0
+B: EV=3
+B: KEY=10000000000000 0
+
+I: Bus=0003 Vendor=046d Product=c52b Version=0111
+N: Name="Logitech USB Receiver"
+H: Handlers=mouse0 event2
+B: PROP=0
+B: EV=17
+B: REL=1943
+
+I: Bus=0018 Vendor=06cb Product=7e7e Version=0100
+N: Name="SynPS/2 Synaptics TouchPad"
+H: Handlers=mouse1 event3
+B: PROP=5
+B: EV=b
+B: KEY=e520 10000 0 0 0 0
+B: ABS=660800011000003
+
+I: Bus=0018 Vendor=04f3 Product=2a1c Version=0100
+N: Name="ELAN Touchscreen"
+H: Handlers=mouse2 event4
+B: PROP=2
+B: EV=b
+B: KEY=400 0 0 0 0 0
+B: ABS=3273800000000003
+`
+
+func TestParseInputDevices(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[string][]string
+	}{
+		{"laptop", testInputDevices, map[string][]string{
+			"Keyboard":    {"AT Translated Set 2 keyboard"},
+			"Mouse":       {"Logitech USB Receiver"},
+			"Touchpad":    {"SynPS/2 Synaptics TouchPad"},
+			"Touchscreen": {"ELAN Touchscreen"},
+		}},
+		{"buttons only", strings.Split(testInputDevices, "\n\n")[1], map[string][]string{}},
+		{"duplicates merged", strings.Split(testInputDevices, "\n\n")[0] + "\n\n" + strings.Split(testInputDevices, "\n\n")[0], map[string][]string{
+			"Keyboard": {"AT Translated Set 2 keyboard"},
+		}},
+		{"empty", "", map[string][]string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseInputDevices(tt.content); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseInputDevices() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package gather
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// journalTimeout bounds the journal scan, which reads every entry of the boot
+// at the error level.
+const journalTimeout = 10 * time.Second
+
+// journalPriorityRe matches the PRIORITY of a journalctl -o json line; 0-2 are
+// emerg, alert and crit, 3 is err.
+var journalPriorityRe = regexp.MustCompile(`"PRIORITY":"([0-7])"`)
+
+// getJournalErrors counts the error and critical messages logged since boot,
+// e.g. "14 (3 critical)", from the journal or, without systemd, the kernel
+// ring buffer ("2 (dmesg)"). Without access to the system journal journalctl
+// only shows the user's own messages, so run it as root or in the
+// systemd-journal group for the whole picture.
+func getJournalErrors(ctx context.Context) (string, error) {
+	errs, crit, err := journalErrorCounts(ctx)
+	source := ""
+	if err != nil {
+		var dmesgErr error
+		if errs, crit, dmesgErr = dmesgErrorCounts(ctx); dmesgErr != nil {
+			return "", err
+		}
+		source = "dmesg"
+	}
+	var notes []string
+	if crit > 0 {
+		notes = append(notes, fmt.Sprintf("%d critical", crit))
+	}
+	if source != "" {
+		notes = append(notes, source)
+	}
+	if len(notes) == 0 {
+		return fmt.Sprint(errs), nil
+	}
+	return fmt.Sprintf("%d (%s)", errs, strings.Join(notes, ", ")), nil
+}
+
+func journalErrorCounts(ctx context.Context) (errs, crit int, err error) {
+	out, err := commandOutputTimeout(ctx, journalTimeout, "journalctl", "-b", "-p", "err", "-q", "--no-pager", "-o", "json", "--output-fields=PRIORITY")
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, match := range journalPriorityRe.FindAllStringSubmatch(out, -1) {
+		errs++
+		if match[1] < "3" {
+			crit++
+		}
+	}
+	return errs, crit, nil
+}
+
+// dmesgErrorCounts reads `dmesg -x`, whose lines start with the facility and
+// level, e.g. "kern  :err   : [    1.234] ...". It fails when
+// kernel.dmesg_restrict keeps unprivileged users out.
+func dmesgErrorCounts(ctx context.Context) (errs, crit int, err error) {
+	out, err := commandOutput(ctx, "dmesg", "-x")
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, line := range strings.Split(out, "\n") {
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) < 3 {
+			continue
+		}
+		switch strings.TrimSpace(parts[1]) {
+		case "emerg", "alert", "crit":
+			crit++
+			errs++
+		case "err":
+			errs++
+		}
+	}
+	return errs, crit, nil
+}
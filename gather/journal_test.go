@@ -0,0 +1,41 @@
+package gather
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDmesgErrorCounts(t *testing.T) {
+	tests := []struct {
+		name       string
+		out        string
+		errs, crit int
+	}{
+		{"empty", "", 0, 0},
+		{"levels", "kern  :err   : [    1.234] ata1: failed\n" +
+			"kern  :crit  : [    2.000] thermal: critical\n" +
+			"kern  :alert : [    2.500] oops\n" +
+			"kern  :warn  : [    3.000] not counted\n" +
+			"user  :info  : [    4.000] hello\n", 3, 2},
+		{"continuation lines skipped", "  no colons here\n", 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useRunner(t, fakeRunner{"dmesg -x": tt.out})
+			errs, crit, err := dmesgErrorCounts(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if errs != tt.errs || crit != tt.crit {
+				t.Errorf("got %d errors, %d critical; want %d, %d", errs, crit, tt.errs, tt.crit)
+			}
+		})
+	}
+}
+
+func TestDmesgErrorCountsRestricted(t *testing.T) {
+	useRunner(t, fakeRunner{})
+	if _, _, err := dmesgErrorCounts(context.Background()); err == nil {
+		t.Error("want an error when dmesg cannot run")
+	}
+}
@@ -0,0 +1,229 @@
+package gather
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// macOS display hardware is read from the IORegistry with `ioreg -a`, which
+// prints a property list in tens of milliseconds, rather than from
+// system_profiler, which takes a second or two to enumerate every bus. Both
+// stay cgo-free; system_profiler remains the fallback for machines whose
+// registry layout is not recognised.
+
+// ioregObjects returns the property dictionaries of the registry entries of class.
+func ioregObjects(ctx context.Context, class string) ([]map[string]any, error) {
+	out, err := commandOutput(ctx, "ioreg", "-a", "-r", "-d", "1", "-c", class)
+	if err != nil || out == "" {
+		return nil, err
+	}
+	v, err := parsePlist([]byte(out))
+	if err != nil {
+		return nil, fmt.Errorf("ioreg: %w", err)
+	}
+	list, _ := v.([]any)
+	var objects []map[string]any
+	for _, item := range list {
+		if m, ok := item.(map[string]any); ok {
+			objects = append(objects, m)
+		}
+	}
+	return objects, nil
+}
+
+// getDarwinGPU names the GPUs: Apple silicon accelerators carry a model
+// string, discrete and Intel GPUs are PCI display devices with a model blob.
+func getDarwinGPU(ctx context.Context) string {
+	var names []string
+	accelerators, _ := ioregObjects(ctx, "IOAccelerator")
+	for _, acc := range accelerators {
+		if model, ok := acc["model"].(string); ok && model != "" {
+			names = append(names, model)
+		}
+	}
+	if len(names) > 0 {
+		return strings.Join(names, "\n")
+	}
+	devices, _ := ioregObjects(ctx, "IOPCIDevice")
+	for _, dev := range devices {
+		if plistString(dev["IOName"]) != "display" {
+			continue
+		}
+		if model := plistString(dev["model"]); model != "" {
+			names = append(names, model)
+		}
+	}
+	return strings.Join(names, "\n")
+}
+
+// getDarwinResolution reports each framebuffer's native mode, as system_profiler does.
+func getDarwinResolution(ctx context.Context) string {
+	framebuffers, _ := ioregObjects(ctx, "IOMobileFramebuffer")
+	var modes []string
+	for _, fb := range framebuffers {
+		attrs, _ := fb["DisplayAttributes"].(map[string]any)
+		w, _ := attrs["NativeFormatHorizontalRes"].(int64)
+		h, _ := attrs["NativeFormatVerticalRes"].(int64)
+		if w > 0 && h > 0 {
+			modes = append(modes, fmt.Sprintf("%dx%d", w, h))
+		}
+	}
+	return strings.Join(modes, "\n")
+}
+
+// plistString reads a string property, which ioreg emits as <data> when the
+// driver stored it as raw NUL-terminated bytes.
+func plistString(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(bytes.TrimRight(v, "\x00"))
+	}
+	return ""
+}
+
+// parsePlist decodes an XML property list into map[string]any, []any, string,
+// int64, float64, bool and []byte values.
+func parsePlist(data []byte) (any, error) {
+	d := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := tok.(xml.StartElement); ok && start.Name.Local != "plist" {
+			return decodePlistValue(d, start)
+		}
+	}
+}
+
+func decodePlistValue(d *xml.Decoder, start xml.StartElement) (any, error) {
+	switch start.Name.Local {
+	case "dict":
+		m := make(map[string]any)
+		var key string
+		for {
+			tok, err := d.Token()
+			if err != nil {
+				return nil, err
+			}
+			switch t := tok.(type) {
+			case xml.StartElement:
+				if t.Name.Local == "key" {
+					if err := d.DecodeElement(&key, &t); err != nil {
+						return nil, err
+					}
+					continue
+				}
+				v, err := decodePlistValue(d, t)
+				if err != nil {
+					return nil, err
+				}
+				m[key] = v
+			case xml.EndElement:
+				return m, nil
+			}
+		}
+	case "array":
+		var list []any
+		for {
+			tok, err := d.Token()
+			if err != nil {
+				return nil, err
+			}
+			switch t := tok.(type) {
+			case xml.StartElement:
+				v, err := decodePlistValue(d, t)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, v)
+			case xml.EndElement:
+				return list, nil
+			}
+		}
+	case "true", "false":
+		return start.Name.Local == "true", d.Skip()
+	}
+	var text string
+	if err := d.DecodeElement(&text, &start); err != nil {
+		return nil, err
+	}
+	switch start.Name.Local {
+	case "integer":
+		text = strings.TrimSpace(text)
+		if n, err := strconv.ParseInt(text, 0, 64); err == nil {
+			return n, nil
+		}
+		// Signed registry values such as InstantAmperage can print as unsigned
+		n, err := strconv.ParseUint(text, 0, 64)
+		return int64(n), err
+	case "real":
+		return strconv.ParseFloat(strings.TrimSpace(text), 64)
+	case "data":
+		return base64.StdEncoding.DecodeString(strings.Join(strings.Fields(text), ""))
+	}
+	return text, nil // string and date
+}
+
+// systemProfilerDisplays returns the value of every "key: value" line in the
+// slow system_profiler display report.
+func systemProfilerDisplays(ctx context.Context, key string) ([]string, error) {
+	out, err := commandOutput(ctx, "system_profiler", "SPDisplaysDataType")
+	var values []string
+	for _, line := range strings.Split(out, "\n") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(line), key+":"); ok {
+			values = append(values, strings.TrimSpace(value))
+		}
+	}
+	return values, err
+}
+
+// getDarwinBrowserID finds the bundle id LaunchServices maps the https scheme
+// to. defaults prints each handler as a dictionary with sorted keys, so
+// LSHandlerRoleAll directly precedes LSHandlerURLScheme.
+func getDarwinBrowserID(ctx context.Context) string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	plist := filepath.Join(home, "Library/Preferences/com.apple.LaunchServices/com.apple.launchservices.secure")
+	lines := strings.Split(runCommand(ctx, "defaults", "read", plist, "LSHandlers"), "\n")
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) != "LSHandlerURLScheme = https;" {
+			continue
+		}
+		if _, quoted, ok := strings.Cut(lines[i-1], "LSHandlerRoleAll = \""); ok {
+			id, _, _ := strings.Cut(quoted, "\"")
+			return id
+		}
+	}
+	return ""
+}
+
+// systemProfilerItems returns the item names of a system_profiler report,
+// the lines indented by four spaces and ending in a colon:
+//
+//	Camera:
+//
+//	    FaceTime HD Camera:
+//
+//	      Model ID: FaceTime HD Camera
+func systemProfilerItems(ctx context.Context, dataType string) ([]string, error) {
+	out, err := commandOutput(ctx, "system_profiler", dataType)
+	var items []string
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "    ") && !strings.HasPrefix(line, "     ") && strings.HasSuffix(line, ":") {
+			items = append(items, strings.TrimSuffix(strings.TrimSpace(line), ":"))
+		}
+	}
+	return items, err
+}
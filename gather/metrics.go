@@ -0,0 +1,96 @@
+package gather
+
+import (
+	"fmt"
+	"time"
+)
+
+// Usage is a used/total reading in bytes (exported for library users).
+type Usage struct {
+	Used    uint64  `json:"used_bytes"`
+	Total   uint64  `json:"total_bytes"`
+	Percent float64 `json:"percent"`
+}
+
+// Metrics are the numbers behind the usage and sensor fields (exported for
+// library users), so they can be checked, compared or formatted differently
+// without parsing the display strings. Nil pointers and zero values mean the
+// reading was not taken (e.g. --fast) or failed; see SystemInfo.Errors.
+type Metrics struct {
+	Uptime        time.Duration `json:"uptime_ns,omitempty"`
+	CPUMHz        float64       `json:"cpu_mhz,omitempty"`         // As reported by the backend
+	CPUCurrentMHz float64       `json:"cpu_current_mhz,omitempty"` // Average over cores
+	CPUBaseMHz    float64       `json:"cpu_base_mhz,omitempty"`
+	CPUMaxMHz     float64       `json:"cpu_max_mhz,omitempty"` // Boost
+	CPUUsage      *float64      `json:"cpu_usage_percent,omitempty"`
+	RAM           *Usage        `json:"ram,omitempty"`
+	Swap          *Usage        `json:"swap,omitempty"`         // Nil without swap
+	Disk          *Usage        `json:"disk,omitempty"`         // The root filesystem
+	NetRX         uint64        `json:"net_rx_bytes,omitempty"` // Primary interface, since boot
+	NetTX         uint64        `json:"net_tx_bytes,omitempty"`
+	NetRXRate     *float64      `json:"net_rx_bytes_per_second,omitempty"` // Sampled over 500 ms
+	NetTXRate     *float64      `json:"net_tx_bytes_per_second,omitempty"`
+	LastUpdate    *time.Time    `json:"last_update,omitempty"` // Last package upgrade
+	Temperature   *float64      `json:"temperature_celsius,omitempty"`
+	SoCTemp       *float64      `json:"soc_temp_celsius,omitempty"`
+	Sensors       []Sensor      `json:"sensors,omitempty"`          // Every temperature sensor, for --sensors
+	PowerDraw     *float64      `json:"power_draw_watts,omitempty"` // Battery discharge, else CPU package
+	FailedUnits   *int          `json:"failed_units,omitempty"`     // systemd units in the failed state
+}
+
+// The string fields are rendered from the metrics with these, so both always agree.
+
+func formatUsage(u *Usage, percentFormat string) string {
+	return fmt.Sprintf("%.1fGB / %.1fGB ("+percentFormat+"%%)", float64(u.Used)/(1<<30), float64(u.Total)/(1<<30), u.Percent)
+}
+
+// formatBytes scales a byte count to the largest unit under 1024 of it.
+func formatBytes(b uint64) string {
+	units := []string{"KB", "MB", "GB", "TB", "PB"}
+	if b < 1024 {
+		return fmt.Sprintf("%d B", b)
+	}
+	v, unit := float64(b)/1024, units[0]
+	for _, u := range units[1:] {
+		if v < 1024 {
+			break
+		}
+		v, unit = v/1024, u
+	}
+	return fmt.Sprintf("%.1f %s", v, unit)
+}
+
+func formatUptime(d time.Duration) string {
+	days := int(d.Hours() / 24)
+	hours := int(d.Hours()) % 24
+	minutes := int(d.Minutes()) % 60
+	if days > 0 {
+		return fmt.Sprintf("%d days, %d hours", days, hours)
+	} else if hours > 0 {
+		return fmt.Sprintf("%d hours, %d minutes", hours, minutes)
+	}
+	return fmt.Sprintf("%d minutes", minutes)
+}
+
+// formatAge describes how long ago something happened in days.
+func formatAge(d time.Duration) string {
+	switch days := int(d.Hours() / 24); {
+	case days < 1:
+		return "today"
+	case days == 1:
+		return "yesterday"
+	default:
+		return fmt.Sprintf("%d days ago", days)
+	}
+}
+
+func formatMHz(mhz float64) string {
+	if mhz > 1000 {
+		return fmt.Sprintf("%.2f GHz", mhz/1000.0)
+	}
+	return fmt.Sprintf("%.0f MHz", mhz)
+}
+
+func formatCelsius(c float64) string {
+	return fmt.Sprintf("%.1f °C", c)
+}
@@ -0,0 +1,226 @@
+package gather
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// Module gathers one or more SystemInfo fields (exported so library users can
+// add their own, or replace a built-in one, with Register).
+type Module interface {
+	Name() string        // Key in Timings and Sources; WithModules matches it too
+	Fields() []string    // The SystemInfo fields it fills, and their keys in Errors
+	Platforms() []string // GOOS values it runs on; nil means every platform
+	Fast() bool          // Cheap enough to run with WithFast
+	Verbose() bool       // Only run with WithVerbose
+	// Gather fills its fields of info, which is a scratch SystemInfo merged
+	// into the result afterwards, and returns the error of each field that
+	// failed. It should give up, and stop the programs it started, when ctx
+	// is done.
+	Gather(ctx context.Context, info *SystemInfo) map[string]error
+}
+
+var (
+	modulesMu sync.Mutex
+	modules   = builtinModules()
+)
+
+// Register adds m to the modules run by GetSystemInfo, or replaces the module
+// of the same name. Its fields must be string fields of SystemInfo.
+func Register(m Module) error {
+	if m.Name() == "" {
+		return fmt.Errorf("register: module without a name")
+	}
+	for _, field := range m.Fields() {
+		if f, ok := reflect.TypeOf(SystemInfo{}).FieldByName(field); !ok || f.Type.Kind() != reflect.String {
+			return fmt.Errorf("register: module %q: %q is not a SystemInfo field", m.Name(), field)
+		}
+	}
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+	for i, existing := range modules {
+		if existing.Name() == m.Name() {
+			modules[i] = m
+			return nil
+		}
+	}
+	modules = append(modules, m)
+	return nil
+}
+
+// Modules lists the registered modules in the order they are started.
+func Modules() []Module {
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+	return slices.Clone(modules)
+}
+
+// OptInModule is implemented by modules that only run when named in
+// WithOptIn, such as ones that can take tens of seconds.
+type OptInModule interface {
+	Module
+	OptIn() bool
+}
+
+// selectModules picks the modules a run with o starts. Opt-in modules run
+// when asked for, even with WithFast; in SSH sessions, the local display
+// ones only when named in WithModules.
+func selectModules(o *runOptions) []Module {
+	remote := sshSession()
+	var selected []Module
+	for _, m := range Modules() {
+		if opt, ok := m.(OptInModule); ok && opt.OptIn() {
+			if slices.Contains(o.optIn, m.Name()) && o.wanted(m) {
+				selected = append(selected, m)
+			}
+			continue
+		}
+		if (o.fast && !m.Fast()) || (m.Verbose() && !o.verbose) || !o.wanted(m) || (remote && o.modules == nil && localDisplayOnly(m)) {
+			continue
+		}
+		selected = append(selected, m)
+	}
+	return selected
+}
+
+// Built-in modules are either a group, which fills several fields in one go,
+// or a single field with a getter.
+type (
+	groupModule struct {
+		name          string
+		fields        []string
+		platforms     []string
+		fast, verbose bool
+		gather        func(context.Context, *SystemInfo, *errorSet)
+	}
+	fieldModule struct {
+		field         string
+		platforms     []string
+		fast, verbose bool
+		optIn         bool                                  // See OptInModule
+		get           func(context.Context) (string, error) // Getters read the run's options with optionsFrom
+	}
+)
+
+func (m groupModule) Name() string        { return m.name }
+func (m groupModule) Fields() []string    { return m.fields }
+func (m groupModule) Platforms() []string { return m.platforms }
+func (m groupModule) Fast() bool          { return m.fast }
+func (m groupModule) Verbose() bool       { return m.verbose }
+
+func (m groupModule) Gather(ctx context.Context, info *SystemInfo) map[string]error {
+	var errs errorSet
+	m.gather(ctx, info, &errs)
+	return errs.errs
+}
+
+func (m fieldModule) Name() string        { return m.field }
+func (m fieldModule) Fields() []string    { return []string{m.field} }
+func (m fieldModule) Platforms() []string { return m.platforms }
+func (m fieldModule) Fast() bool          { return m.fast }
+func (m fieldModule) Verbose() bool       { return m.verbose }
+func (m fieldModule) OptIn() bool         { return m.optIn }
+
+func (m fieldModule) Gather(ctx context.Context, info *SystemInfo) map[string]error {
+	value, err := m.get(ctx)
+	setField(info, m.field, value)
+	if err != nil {
+		return map[string]error{m.field: err}
+	}
+	return nil
+}
+
+func setField(info *SystemInfo, field, value string) {
+	reflect.ValueOf(info).Elem().FieldByName(field).SetString(value)
+}
+
+func getField(info *SystemInfo, field string) string {
+	return reflect.ValueOf(info).Elem().FieldByName(field).String()
+}
+
+// Module kinds for the table below.
+func fast(field string, get func(context.Context) (string, error)) Module {
+	return fieldModule{field: field, fast: true, get: get}
+}
+
+func slow(field string, get func(context.Context) (string, error)) Module {
+	return fieldModule{field: field, get: get}
+}
+
+func verbose(field string, get func(context.Context) (string, error)) Module {
+	return fieldModule{field: field, fast: true, verbose: true, get: get}
+}
+
+// builtinModules lists every built-in field in start order; a new field is
+// one more line here.
+func builtinModules() []Module {
+	return []Module{
+		groupModule{name: "HostInfo", fields: []string{"Hostname", "OS", "Kernel", "Arch", "Uptime", "BootTime"}, fast: true, gather: gatherHostInfo},
+		groupModule{name: "CPUInfo", fields: []string{"CPU", "CoresThreads", "CPUSpeed"}, fast: true, gather: gatherCPUInfo},
+		groupModule{name: "MemoryInfo", fields: []string{"RAM", "Swap"}, fast: true, gather: gatherMemoryInfo},
+		groupModule{name: "Disk", fields: []string{"Disk"}, fast: true, gather: gatherDisk},
+
+		fast("Shell", getShell),
+		fast("GPU", getGPUInfo),
+		fast("IPAddress", getIPAddress),
+		groupModule{name: "NetTraffic", fields: []string{"NetTraffic"}, fast: true, gather: gatherTraffic},
+		fast("Locale", getSystemLocale),
+		fast("Resolution", getResolution),
+		fast("WindowManager", getWindowManager),
+		fast("DE", getDesktopEnvironment),
+		fast("Terminal", getTerminal),
+		fast("Session", getSession),
+		fast("Go", getGoVersion),
+		fast("Virtualization", getVirtualization),
+		fast("Editor", getEditor),
+		fast("WMPlugins", getWMPlugins),
+		fast("Timezone", getTimezone),
+		fast("Board", getBoardModel),
+		fieldModule{field: "CrashDumps", fast: true, get: getCrashDumps},
+		fieldModule{field: "InputDevices", platforms: []string{"linux", "windows"}, fast: true, get: getInputDevices},
+		fieldModule{field: "Battery", platforms: []string{"linux", "darwin", "windows"}, fast: true, get: getBattery},
+		fast("DisplayServer", getDisplayServer),
+		fast("Autostart", getAutostart),
+		fieldModule{field: "DisplayManager", platforms: []string{"linux"}, fast: true, get: getDisplayManager},
+		fieldModule{field: "Portal", platforms: []string{"linux"}, fast: true, get: getPortalBackend},
+
+		groupModule{name: "CPUUsage", fields: []string{"CPUUsage"}, gather: gatherCPUUsage},
+		groupModule{name: "Bandwidth", fields: []string{"Bandwidth"}, gather: gatherBandwidth},
+		groupModule{name: "Temperature", fields: []string{"Temperature"}, gather: gatherTemperature},
+		groupModule{name: "PowerDraw", fields: []string{"PowerDraw"}, platforms: []string{"linux", "darwin", "windows"}, gather: gatherPowerDraw},
+		groupModule{name: "SoCTemp", fields: []string{"SoCTemp"}, gather: gatherSoCTemp},
+		groupModule{name: "FailedUnits", fields: []string{"FailedUnits"}, platforms: []string{"linux"}, gather: gatherFailedUnits},
+		fieldModule{field: "OpenPorts", get: getOpenPorts},
+		fieldModule{field: "Connections", get: getConnections},
+		fieldModule{field: "Camera", platforms: []string{"linux", "darwin", "windows"}, get: getCameras},
+		fieldModule{field: "Drives", platforms: []string{"linux", "darwin", "windows"}, get: getDrives},
+		slow("Packages", getPackageCounts),
+		slow("Fonts", getFontCount),
+		fieldModule{field: "PendingUpdates", optIn: true, get: getPendingUpdates},
+		fieldModule{field: "USBDevices", platforms: []string{"linux", "darwin", "windows"}, optIn: true, get: getUSBDevices},
+		fieldModule{field: "Printers", optIn: true, get: getPrinters},
+		fieldModule{field: "ScheduledJobs", optIn: true, get: getScheduledJobs},
+		fieldModule{field: "JournalErrors", platforms: []string{"linux"}, optIn: true, get: getJournalErrors},
+		groupModule{name: "LastUpdate", fields: []string{"LastUpdate"}, gather: gatherLastUpdate},
+		slow("Languages", getInstalledLanguages),
+		slow("Browser", getDefaultBrowser),
+		slow("NTPSync", getNTPSync),
+		slow("Throttling", getThrottling),
+		fieldModule{field: "HybridGraphics", platforms: []string{"linux"}, get: getHybridGraphics},
+		fieldModule{field: "GraphicsAPI", platforms: []string{"linux", "freebsd", "openbsd", "netbsd"}, get: getGraphicsAPI},
+		slow("Compute", getComputeToolkits),
+		fieldModule{field: "Font", platforms: []string{"linux", "freebsd", "openbsd", "netbsd"}, get: getFontConfig},
+		slow("Wallpaper", getWallpaper),
+
+		verbose("KernelModules", getKernelModules),
+		fieldModule{field: "KernelTaint", platforms: []string{"linux"}, fast: true, verbose: true, get: getKernelTaint},
+		verbose("FQDN", getFQDN),
+		verbose("Domain", getDomainMembership),
+		verbose("RunningVMs", getRunningVMs),
+		groupModule{name: "TopProcesses", fields: []string{"TopCPU", "TopMemory"}, fast: true, verbose: true, gather: gatherTopProcesses},
+		fieldModule{field: "PreviousBoots", platforms: []string{"linux", "windows"}, fast: true, verbose: true, get: getPreviousBoots},
+	}
+}
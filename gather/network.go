@@ -0,0 +1,146 @@
+package gather
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+)
+
+// gatherTraffic reports the bytes received and sent by the primary interface
+// since boot, e.g. "RX 12.3 GB, TX 1.2 GB (wlan0)".
+func gatherTraffic(ctx context.Context, info *SystemInfo, errs *errorSet) {
+	counters, err := backend.NetIOCounters()
+	if err != nil {
+		errs.record("NetTraffic", classifyError("network counters", err))
+		return
+	}
+	primary := primaryCounter(counters, primaryInterface())
+	if primary == nil {
+		return
+	}
+	info.Metrics.NetRX, info.Metrics.NetTX = primary.BytesRecv, primary.BytesSent
+	info.NetTraffic = fmt.Sprintf("RX %s, TX %s (%s)", formatBytes(primary.BytesRecv), formatBytes(primary.BytesSent), primary.Name)
+}
+
+// bandwidthSample is how long gatherBandwidth watches the counters.
+const bandwidthSample = 500 * time.Millisecond
+
+// gatherBandwidth samples the primary interface's counters twice to report
+// the current rates, e.g. "↓ 2.4 MB/s ↑ 120.0 KB/s".
+func gatherBandwidth(ctx context.Context, info *SystemInfo, errs *errorSet) {
+	name := primaryInterface()
+	before, err := backend.NetIOCounters()
+	if err != nil {
+		errs.record("Bandwidth", classifyError("network counters", err))
+		return
+	}
+	start := time.Now()
+	time.Sleep(bandwidthSample)
+	after, err := backend.NetIOCounters()
+	if err != nil {
+		errs.record("Bandwidth", classifyError("network counters", err))
+		return
+	}
+	last := primaryCounter(after, name)
+	if last == nil {
+		return
+	}
+	var first *NetIOStat
+	for i := range before {
+		if before[i].Name == last.Name {
+			first = &before[i]
+		}
+	}
+	if first == nil || last.BytesRecv < first.BytesRecv || last.BytesSent < first.BytesSent {
+		return // The interface appeared or was reset while sampling
+	}
+	seconds := time.Since(start).Seconds()
+	rx := float64(last.BytesRecv-first.BytesRecv) / seconds
+	tx := float64(last.BytesSent-first.BytesSent) / seconds
+	info.Metrics.NetRXRate, info.Metrics.NetTXRate = &rx, &tx
+	info.Bandwidth = fmt.Sprintf("↓ %s/s ↑ %s/s", formatBytes(uint64(rx)), formatBytes(uint64(tx)))
+}
+
+// primaryCounter picks the named interface, or offline (name "") the
+// busiest one that is not a loopback.
+func primaryCounter(counters []NetIOStat, name string) *NetIOStat {
+	var primary *NetIOStat
+	for i, c := range counters {
+		switch {
+		case name != "":
+			if c.Name == name {
+				primary = &counters[i]
+			}
+		case !isLoopback(c.Name) && (primary == nil || c.BytesRecv+c.BytesSent > primary.BytesRecv+primary.BytesSent):
+			primary = &counters[i]
+		}
+	}
+	return primary
+}
+
+// topPeers is how many remote hosts verbose mode lists in Connections.
+const topPeers = 3
+
+// getConnections counts established TCP connections, e.g. "42 established";
+// in verbose mode the busiest remote hosts follow, e.g.
+// "42 established (140.82.112.4: 12, 1.1.1.1: 3, 10.0.0.2: 2)".
+func getConnections(ctx context.Context) (string, error) {
+	conns, err := backend.Connections("tcp")
+	if err != nil {
+		return "", classifyError("connections", err)
+	}
+	perHost := make(map[string]int)
+	total := 0
+	for _, conn := range conns {
+		if conn.Status == "ESTABLISHED" {
+			total++
+			perHost[conn.RemoteIP]++
+		}
+	}
+	summary := fmt.Sprintf("%d established", total)
+	if !optionsFrom(ctx).verbose || total == 0 {
+		return summary, nil
+	}
+	hosts := make([]string, 0, len(perHost))
+	for host := range perHost {
+		hosts = append(hosts, host)
+	}
+	sort.Slice(hosts, func(i, j int) bool {
+		if perHost[hosts[i]] != perHost[hosts[j]] {
+			return perHost[hosts[i]] > perHost[hosts[j]]
+		}
+		return hosts[i] < hosts[j]
+	})
+	var top []string
+	for _, host := range hosts[:min(topPeers, len(hosts))] {
+		top = append(top, fmt.Sprintf("%s: %d", host, perHost[host]))
+	}
+	return summary + " (" + strings.Join(top, ", ") + ")", nil
+}
+
+// primaryInterface names the interface holding the default route's address,
+// or "" offline.
+func primaryInterface() string {
+	ip := routedIP()
+	if ip == nil {
+		return ""
+	}
+	ifaces, _ := net.Interfaces()
+	for _, iface := range ifaces {
+		addrs, _ := iface.Addrs()
+		for _, addr := range addrs {
+			if ipnet, ok := addr.(*net.IPNet); ok && ipnet.IP.Equal(ip) {
+				return iface.Name
+			}
+		}
+	}
+	return ""
+}
+
+func isLoopback(name string) bool {
+	iface, err := net.InterfaceByName(name)
+	return err == nil && iface.Flags&net.FlagLoopback != 0
+}
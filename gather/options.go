@@ -0,0 +1,131 @@
+package gather
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+// Option adjusts one GetSystemInfo call (exported for library users). With no
+// options every field except the verbose ones is gathered, with no cache,
+// deadline or limit on concurrent gatherers.
+type Option func(*runOptions)
+
+type runOptions struct {
+	fast, verbose bool
+	modules       []string // SystemInfo field names; nil gathers everything selected by fast/verbose
+	timeout       time.Duration
+	cacheTTL      time.Duration // Zero disables the cache
+	cacheRefresh  bool
+	slowBudget    time.Duration // Zero never skips slow tasks
+	jobs          int           // <= 0 is unbounded
+	trace         bool
+	allPorts      bool
+	optIn         []string // Opt-in module names
+}
+
+// WithFast skips the slow fields (GPU, packages, temperatures and so on),
+// like --fast.
+func WithFast() Option {
+	return func(o *runOptions) { o.fast = true }
+}
+
+// WithVerbose also gathers the verbose-only fields such as FQDN and RunningVMs.
+func WithVerbose() Option {
+	return func(o *runOptions) { o.verbose = true }
+}
+
+// WithModules restricts the run to the named modules (see Modules) and the
+// modules that fill the named SystemInfo fields, e.g. WithModules("CPU",
+// "RAM"). Fields gathered together with a named one (such as Kernel with OS)
+// are filled as well.
+// Fields excluded by WithFast or left out of WithVerbose still are not run.
+func WithModules(fields ...string) Option {
+	return func(o *runOptions) { o.modules = append(o.modules, fields...) }
+}
+
+// WithTimeout makes GetSystemInfo return after d with whatever was collected;
+// fields still being gathered are left empty with an ErrSkipped error.
+// Cancelling the context passed to GetSystemInfo has the same effect.
+func WithTimeout(d time.Duration) Option {
+	return func(o *runOptions) { o.timeout = d }
+}
+
+// WithCache enables the on-disk cache of slow, rarely changing fields for ttl.
+// With refresh set, cached values are ignored but fresh ones are still stored.
+func WithCache(ttl time.Duration, refresh bool) Option {
+	return func(o *runOptions) {
+		o.cacheTTL = ttl
+		o.cacheRefresh = refresh
+	}
+}
+
+// WithSlowBudget skips, while the cache is enabled, tasks that took longer
+// than budget in each of their last three runs on this machine (such as a
+// package manager that takes seconds to list). Their fields are left empty
+// with an ErrTooSlow error until the cache TTL has passed since they last
+// ran, when they get another chance. Without it every task runs, still
+// recording how long it took.
+func WithSlowBudget(budget time.Duration) Option {
+	return func(o *runOptions) { o.slowBudget = budget }
+}
+
+// WithJobs limits concurrent gatherers (and so the external commands they
+// spawn) to n; n <= 0 removes the limit.
+func WithJobs(n int) Option {
+	return func(o *runOptions) { o.jobs = n }
+}
+
+// WithAllPorts makes OpenPorts list every listening TCP and bound UDP socket
+// with its address, e.g. "0.0.0.0:22/tcp, 127.0.0.53:53/udp", instead of the
+// first five TCP port numbers.
+func WithAllPorts() Option {
+	return func(o *runOptions) { o.allPorts = true }
+}
+
+// WithOptIn also runs the named opt-in modules (see OptInModule), such as
+// "PendingUpdates", which counts upgradable packages and can take tens of
+// seconds, "JournalErrors", "USBDevices", "Printers" or "ScheduledJobs".
+func WithOptIn(names ...string) Option {
+	return func(o *runOptions) { o.optIn = append(o.optIn, names...) }
+}
+
+// WithTrace records SystemInfo.Sources. Gatherers then run one at a time,
+// like WithJobs(1), so every read can be put down to the gatherer that made
+// it; expect collection to take longer. Only one traced run may be in
+// progress at a time.
+func WithTrace() Option {
+	return func(o *runOptions) { o.trace = true }
+}
+
+// optionsKey stores the run's options in the context modules get.
+type optionsKey struct{}
+
+// optionsFrom returns the options of the run ctx belongs to.
+func optionsFrom(ctx context.Context) *runOptions {
+	if o, ok := ctx.Value(optionsKey{}).(*runOptions); ok {
+		return o
+	}
+	return &runOptions{}
+}
+
+func newOptions(opts []Option) *runOptions {
+	o := &runOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// wanted reports whether m is selected by WithModules.
+func (o *runOptions) wanted(m Module) bool {
+	if o.modules == nil || slices.Contains(o.modules, m.Name()) {
+		return true
+	}
+	for _, field := range m.Fields() {
+		if slices.Contains(o.modules, field) {
+			return true
+		}
+	}
+	return false
+}
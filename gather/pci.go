@@ -0,0 +1,179 @@
+package gather
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// pciIDsPaths are the usual locations of the hwdata/pciutils name database.
+var pciIDsPaths = []string{
+	"/usr/share/hwdata/pci.ids",
+	"/usr/share/misc/pci.ids",
+	"/usr/share/pci.ids",
+	"/usr/share/misc/pci.ids.gz",
+}
+
+// pciVendors names common display vendors when no pci.ids is installed, as in
+// minimal containers.
+var pciVendors = map[uint16]string{
+	0x1002: "AMD/ATI",
+	0x102b: "Matrox",
+	0x106b: "Apple",
+	0x10de: "NVIDIA",
+	0x1234: "QEMU",
+	0x13b5: "ARM",
+	0x15ad: "VMware",
+	0x1a03: "ASPEED",
+	0x1af4: "Red Hat Virtio",
+	0x1b36: "Red Hat QXL",
+	0x1de1: "Moore Threads",
+	0x5143: "Qualcomm",
+	0x80ee: "VirtualBox",
+	0x8086: "Intel",
+}
+
+type pciDevice struct {
+	path           string // The sysfs directory
+	vendor, device uint16
+	bootVGA        bool
+}
+
+// getLinuxGPU names the display controllers from sysfs, boot display first,
+// without lspci.
+func getLinuxGPU() (string, error) {
+	gpus := linuxGPUs()
+	if len(gpus) == 0 {
+		return "", nil // No PCI bus, as on most ARM boards
+	}
+	names := lookupPCINames(gpus)
+	return strings.Join(names, "\n"), nil
+}
+
+// linuxGPUs lists the PCI display controllers (class 0x03), boot display first.
+func linuxGPUs() []pciDevice {
+	traceRead("file", "/sys/bus/pci/devices/*")
+	paths, _ := filepath.Glob("/sys/bus/pci/devices/*")
+	var gpus []pciDevice
+	for _, path := range paths {
+		class := readSysfsHex(filepath.Join(path, "class"))
+		if class>>16 != 0x03 {
+			continue
+		}
+		dev := pciDevice{
+			path:    path,
+			vendor:  uint16(readSysfsHex(filepath.Join(path, "vendor"))),
+			device:  uint16(readSysfsHex(filepath.Join(path, "device"))),
+			bootVGA: readSysfsHex(filepath.Join(path, "boot_vga")) == 1,
+		}
+		if dev.bootVGA {
+			gpus = append([]pciDevice{dev}, gpus...)
+		} else {
+			gpus = append(gpus, dev)
+		}
+	}
+	return gpus
+}
+
+func readSysfsHex(path string) uint64 {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.ParseUint(strings.TrimPrefix(strings.TrimSpace(string(content)), "0x"), 16, 32)
+	return n
+}
+
+// lookupPCINames resolves vendor and device names from pci.ids, falling back
+// to the built-in vendor table and the raw device id.
+func lookupPCINames(devices []pciDevice) []string {
+	vendors := make(map[uint16]string)
+	models := make(map[[2]uint16]string)
+	scanPCIIDs(devices, vendors, models)
+	names := make([]string, 0, len(devices))
+	for _, d := range devices {
+		vendor := vendors[d.vendor]
+		if vendor == "" {
+			vendor = pciVendors[d.vendor]
+		}
+		if vendor == "" {
+			vendor = fmt.Sprintf("Vendor %04x", d.vendor)
+		}
+		model := models[[2]uint16{d.vendor, d.device}]
+		if model == "" {
+			model = fmt.Sprintf("Device %04x", d.device)
+		}
+		names = append(names, vendor+" "+model)
+	}
+	return names
+}
+
+// scanPCIIDs fills in the names of devices from the first pci.ids found.
+// Vendor lines are "vvvv  Name"; their device lines follow, indented by one tab.
+func scanPCIIDs(devices []pciDevice, vendors map[uint16]string, models map[[2]uint16]string) {
+	var r io.Reader
+	for _, path := range pciIDsPaths {
+		f, err := os.Open(path)
+		if err != nil {
+			continue
+		}
+		traceRead("file", path)
+		defer f.Close()
+		r = f
+		if strings.HasSuffix(path, ".gz") {
+			if r, err = gzip.NewReader(f); err != nil {
+				return
+			}
+		}
+		break
+	}
+	if r == nil {
+		return
+	}
+	wanted := make(map[uint16]bool)
+	wantedModels := make(map[[2]uint16]bool)
+	for _, d := range devices {
+		wanted[d.vendor] = true
+		wantedModels[[2]uint16{d.vendor, d.device}] = true
+	}
+	var vendor uint16
+	inVendor := false
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "" || line[0] == '#':
+		case strings.HasPrefix(line, "C "):
+			return // Device classes follow the vendor list
+		case line[0] != '\t':
+			idText, name, _ := strings.Cut(line, "  ")
+			id, err := strconv.ParseUint(idText, 16, 16)
+			vendor, inVendor = uint16(id), err == nil && wanted[uint16(id)]
+			if inVendor {
+				vendors[vendor] = shortPCIName(name)
+			}
+		case inVendor && !strings.HasPrefix(line, "\t\t"): // Subsystem lines are indented twice
+			idText, name, _ := strings.Cut(line[1:], "  ")
+			if id, err := strconv.ParseUint(idText, 16, 16); err == nil {
+				if key := [2]uint16{vendor, uint16(id)}; wantedModels[key] {
+					models[key] = shortPCIName(name)
+				}
+			}
+		}
+	}
+}
+
+// shortPCIName prefers the marketing name in brackets, e.g. "TU106 [GeForce
+// RTX 2060]" becomes "GeForce RTX 2060".
+func shortPCIName(name string) string {
+	name = strings.TrimSpace(name)
+	if i := strings.LastIndexByte(name, '['); i >= 0 && strings.HasSuffix(name, "]") {
+		return name[i+1 : len(name)-1]
+	}
+	return name
+}
@@ -0,0 +1,69 @@
+package gather
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestShortPCIName(t *testing.T) {
+	tests := []struct{ name, want string }{
+		{"TU106 [GeForce RTX 2060]", "GeForce RTX 2060"},
+		{"Navi 21 [Radeon RX 6800/6800 XT / 6900 XT]", "Radeon RX 6800/6800 XT / 6900 XT"},
+		{"  Virtio 1.0 GPU  ", "Virtio 1.0 GPU"},
+		{"Advanced Micro Devices, Inc. [AMD/ATI]", "AMD/ATI"},
+		{"Odd [bracket] name", "Odd [bracket] name"},
+	}
+	for _, tt := range tests {
+		if got := shortPCIName(tt.name); got != tt.want {
+			t.Errorf("shortPCIName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+const testPCIIDs = `# pci.ids excerpt
+10de  NVIDIA Corporation
+	1f08  TU106 [GeForce RTX 2060 Rev. A]
+		1043 86f3  Subsystem line, not a device
+	2204  GA102 [GeForce RTX 3090]
+1af4  Red Hat, Inc.
+	1050  Virtio 1.0 GPU
+8086  Intel Corporation
+	46a6  Alder Lake-P GT2 [Iris Xe Graphics]
+C 03  Display controller
+	00  VGA compatible controller
+`
+
+func TestScanPCIIDs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pci.ids")
+	if err := os.WriteFile(path, []byte(testPCIIDs), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	saved := pciIDsPaths
+	pciIDsPaths = []string{filepath.Join(t.TempDir(), "missing"), path}
+	t.Cleanup(func() { pciIDsPaths = saved })
+
+	devices := []pciDevice{{vendor: 0x10de, device: 0x1f08}, {vendor: 0x1af4, device: 0x1050}, {vendor: 0x1234, device: 0x1111}}
+	vendors := make(map[uint16]string)
+	models := make(map[[2]uint16]string)
+	scanPCIIDs(devices, vendors, models)
+
+	wantVendors := map[uint16]string{0x10de: "NVIDIA Corporation", 0x1af4: "Red Hat, Inc."}
+	wantModels := map[[2]uint16]string{{0x10de, 0x1f08}: "GeForce RTX 2060 Rev. A", {0x1af4, 0x1050}: "Virtio 1.0 GPU"}
+	if len(vendors) != len(wantVendors) {
+		t.Errorf("vendors = %v, want %v", vendors, wantVendors)
+	}
+	for id, want := range wantVendors {
+		if vendors[id] != want {
+			t.Errorf("vendor %04x = %q, want %q", id, vendors[id], want)
+		}
+	}
+	if len(models) != len(wantModels) {
+		t.Errorf("models = %v, want %v", models, wantModels)
+	}
+	for key, want := range wantModels {
+		if models[key] != want {
+			t.Errorf("model %04x:%04x = %q, want %q", key[0], key[1], models[key], want)
+		}
+	}
+}
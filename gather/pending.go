@@ -0,0 +1,146 @@
+package gather
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// pendingTimeout bounds each update check; they may refresh package metadata
+// over the network.
+const pendingTimeout = time.Minute
+
+// updateCheckers count upgradable packages, named like packageCheckers.
+// quietExits are exit codes that still mean success (checkupdates exits 2
+// when nothing is pending, dnf check-update 100 when something is).
+var updateCheckers = map[string][]struct {
+	name       string
+	args       []string
+	quietExits []int
+	count      func(out string) int
+}{
+	"linux": {
+		{"APT", []string{"apt", "list", "--upgradable"}, nil, countContaining("[upgradable from")},
+		{"Pacman", []string{"checkupdates"}, []int{2}, countLines},
+		{"DNF", []string{"dnf", "check-update", "--quiet"}, []int{100}, countDNFUpdates},
+		{"Zypper", []string{"zypper", "--quiet", "list-updates"}, nil, countPrefix("v ")},
+	},
+	"darwin": {
+		{"Brew", []string{"brew", "outdated", "--quiet"}, nil, countLines},
+	},
+	"windows": {
+		{"Winget", []string{"winget", "upgrade"}, nil, countWingetUpgrades},
+	},
+}
+
+// getPendingUpdates reports how many packages can be upgraded per package
+// manager, e.g. "APT (12), Flatpak (3)". It is opt-in (--updates) because the
+// checks can take tens of seconds.
+func getPendingUpdates(ctx context.Context) (string, error) {
+	checkers, ok := updateCheckers[runtime.GOOS]
+	if !ok {
+		return "", errUnsupported()
+	}
+	var results []string
+	var lastErr error
+	for _, c := range checkers {
+		if _, err := runner.LookPath(c.args[0]); err != nil {
+			continue
+		}
+		out, err := checkOutput(ctx, c.quietExits, c.args[0], c.args[1:]...)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		results = append(results, fmt.Sprintf("%s (%d)", c.name, c.count(out)))
+	}
+	if len(results) == 0 {
+		return "", lastErr
+	}
+	return strings.Join(results, ", "), nil
+}
+
+// checkOutput is commandOutputTimeout for checkers whose exit code carries
+// the result: the output is kept when the program exits with a quiet code.
+func checkOutput(ctx context.Context, quietExits []int, name string, arg ...string) (string, error) {
+	if err := checkCommands(name); err != nil {
+		return "", err
+	}
+	traceRead("command", name)
+	ctx, cancel := context.WithTimeout(ctx, pendingTimeout)
+	defer cancel()
+	var out string
+	err := withOutput(ctx, func(b []byte) { out = string(b) }, name, arg...)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && slices.Contains(quietExits, exitErr.ExitCode()) {
+		err = nil
+	}
+	if ctx.Err() != nil {
+		return "", classifyError(name, ctx.Err())
+	}
+	if err != nil {
+		return "", classifyError(name, err)
+	}
+	return out, nil
+}
+
+func countLines(out string) int {
+	n := 0
+	for _, line := range strings.Split(out, "\n") {
+		if strings.TrimSpace(line) != "" {
+			n++
+		}
+	}
+	return n
+}
+
+func countContaining(substr string) func(string) int {
+	return func(out string) int {
+		return strings.Count(out, substr)
+	}
+}
+
+func countPrefix(prefix string) func(string) int {
+	return func(out string) int {
+		n := 0
+		for _, line := range strings.Split(out, "\n") {
+			if strings.HasPrefix(line, prefix) {
+				n++
+			}
+		}
+		return n
+	}
+}
+
+// countDNFUpdates counts "name.arch version repo" lines, stopping at the
+// "Obsoleting Packages" section.
+func countDNFUpdates(out string) int {
+	n := 0
+	for _, line := range strings.Split(out, "\n") {
+		if strings.HasPrefix(line, "Obsoleting") {
+			break
+		}
+		if len(strings.Fields(line)) == 3 {
+			n++
+		}
+	}
+	return n
+}
+
+// wingetSummaryRe matches the last line of "winget upgrade", e.g. "12 upgrades available."
+var wingetSummaryRe = regexp.MustCompile(`(\d+) upgrades? available`)
+
+func countWingetUpgrades(out string) int {
+	if match := wingetSummaryRe.FindStringSubmatch(out); match != nil {
+		n, _ := strconv.Atoi(match[1])
+		return n
+	}
+	return 0
+}
@@ -0,0 +1,117 @@
+package gather
+
+import (
+	"fmt"
+	"log"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// CommandPolicy restricts which external programs gatherers may execute
+// (exported for the config package). Names are matched against the program's
+// base name, case-insensitively and without ".exe".
+type CommandPolicy struct {
+	Allow []string `json:"allow"` // When non-empty, only these programs may run
+	Deny  []string `json:"deny"`  // Always refused, even if allowed
+	None  bool     `json:"none"`  // Refuse every program, without logging each one
+}
+
+var (
+	policy       CommandPolicy
+	policyMu     sync.Mutex
+	loggedDenied = make(map[string]bool)
+)
+
+// SetCommandPolicy installs the policy enforced by every external command gather runs.
+func SetCommandPolicy(p CommandPolicy) {
+	policy = p
+}
+
+func normalizeCommandName(name string) string {
+	name = strings.ToLower(filepath.Base(name))
+	return strings.TrimSuffix(name, ".exe")
+}
+
+func (p CommandPolicy) permits(name string) bool {
+	if p.None {
+		return false
+	}
+	name = normalizeCommandName(name)
+	for _, d := range p.Deny {
+		if normalizeCommandName(d) == name {
+			return false
+		}
+	}
+	if len(p.Allow) == 0 {
+		return true
+	}
+	for _, a := range p.Allow {
+		if normalizeCommandName(a) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// checkCommands verifies every program against the policy, logging each denied
+// program once per run.
+func checkCommands(programs ...string) error {
+	for _, program := range programs {
+		if policy.permits(program) {
+			continue
+		}
+		name := normalizeCommandName(program)
+		policyMu.Lock()
+		if !loggedDenied[name] && !policy.None {
+			loggedDenied[name] = true
+			log.Printf("command policy: refused to run %q", name)
+		}
+		policyMu.Unlock()
+		return fmt.Errorf("%s: %w", name, ErrCommandDenied)
+	}
+	return nil
+}
+
+// scriptPrograms lists the programs a shell snippet would start: the first word
+// of every pipeline or list segment, ignoring quoted text. PowerShell cmdlets
+// (Verb-Noun) and expressions are builtins, not programs.
+func scriptPrograms(script string) []string {
+	var segments []string
+	var current strings.Builder
+	var quote rune
+	for _, r := range script {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+			current.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote = r
+			current.WriteRune(r)
+		case r == '|' || r == ';' || r == '&':
+			segments = append(segments, current.String())
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	segments = append(segments, current.String())
+
+	var programs []string
+	for _, segment := range segments {
+		for _, word := range strings.Fields(strings.TrimLeft(segment, " \t(")) {
+			if strings.Contains(word, "=") && runtime.GOOS != "windows" {
+				continue // Leading VAR=value assignment
+			}
+			if runtime.GOOS == "windows" && (strings.Contains(word, "-") || strings.ContainsAny(word[:1], "$[@")) {
+				break // Cmdlet or expression
+			}
+			programs = append(programs, word)
+			break
+		}
+	}
+	return programs
+}
@@ -0,0 +1,28 @@
+package gather
+
+import (
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestScriptPrograms(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("POSIX shell snippets")
+	}
+	tests := []struct {
+		script string
+		want   []string
+	}{
+		{"dpkg-query -f . -W | wc -l", []string{"dpkg-query", "wc"}},
+		{"LANG=C snap list | tail -n +2 | wc -l", []string{"snap", "tail", "wc"}},
+		{`grep 'a|b;c' file && echo "x & y"`, []string{"grep", "echo"}},
+		{"(cd /tmp && ls -l)", []string{"cd", "ls"}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := scriptPrograms(tt.script); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("scriptPrograms(%q) = %q, want %q", tt.script, got, tt.want)
+		}
+	}
+}
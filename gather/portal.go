@@ -0,0 +1,114 @@
+package gather
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// portalPrefix names xdg-desktop-portal and, with a suffix, its backends
+// such as xdg-desktop-portal-gtk.
+const portalPrefix = "xdg-desktop-portal"
+
+// getPortalBackend reports the running xdg-desktop-portal backends and the
+// ones portals.conf prefers, e.g. "hyprland, gtk (preferred: hyprland;gtk)".
+// A frontend without a backend, or no frontend at all, is what breaks screen
+// sharing and file pickers, so both are spelled out.
+func getPortalBackend(ctx context.Context) (string, error) {
+	if os.Getenv("WAYLAND_DISPLAY") == "" && os.Getenv("DISPLAY") == "" {
+		return "", nil // No graphical session
+	}
+	frontend, backends := runningPortals()
+	var value string
+	switch {
+	case len(backends) > 0:
+		value = strings.Join(backends, ", ")
+		if !frontend {
+			value += " (xdg-desktop-portal not running)"
+		}
+	case frontend:
+		value = "none (no backend running)"
+	default:
+		value = "not running"
+	}
+	if preferred := preferredPortals(); preferred != "" {
+		value += " (preferred: " + preferred + ")"
+	}
+	return value, nil
+}
+
+// runningPortals finds the portal processes.
+func runningPortals() (frontend bool, backends []string) {
+	for _, name := range runningPrograms() {
+		switch {
+		case name == portalPrefix:
+			frontend = true
+		case strings.HasPrefix(name, portalPrefix+"-"):
+			backend := strings.TrimPrefix(name, portalPrefix+"-")
+			if !slices.Contains(backends, backend) {
+				backends = append(backends, backend)
+			}
+		}
+	}
+	return frontend, backends
+}
+
+// preferredPortals reads the default= line of the [preferred] section of the
+// first portals.conf found, in the lookup order of xdg-desktop-portal 1.18:
+// desktop-specific before generic, user config before system and data dirs.
+func preferredPortals() string {
+	var dirs []string
+	if configHome := os.Getenv("XDG_CONFIG_HOME"); configHome != "" {
+		dirs = append(dirs, configHome)
+	} else if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".config"))
+	}
+	dirs = append(dirs, "/etc/xdg")
+	for _, dir := range strings.Split(os.Getenv("XDG_DATA_DIRS"), ":") {
+		if dir != "" {
+			dirs = append(dirs, dir)
+		}
+	}
+	dirs = append(dirs, "/usr/local/share", "/usr/share")
+
+	var names []string
+	for _, desktop := range strings.Split(os.Getenv("XDG_CURRENT_DESKTOP"), ":") {
+		if desktop != "" {
+			names = append(names, strings.ToLower(desktop)+"-portals.conf")
+		}
+	}
+	names = append(names, "portals.conf")
+	for _, name := range names {
+		for _, dir := range dirs {
+			if value := portalsConfDefault(filepath.Join(dir, portalPrefix, name)); value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+func portalsConfDefault(path string) string {
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	traceRead("file", path)
+	section := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			section = line
+			continue
+		}
+		if key, value, ok := strings.Cut(line, "="); ok && section == "[preferred]" && strings.TrimSpace(key) == "default" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,138 @@
+package gather
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// powerSample is how long the RAPL energy counters are sampled for.
+const powerSample = 500 * time.Millisecond
+
+// gatherPowerDraw reports the power being drawn: from the battery while
+// discharging (the whole system), and on Linux the CPU package power from the
+// RAPL energy counters, e.g. "14.2 W (battery), 6.8 W (CPU package)".
+// Metrics.PowerDraw is the battery figure, or the CPU one on AC.
+func gatherPowerDraw(ctx context.Context, info *SystemInfo, errs *errorSet) {
+	var parts []string
+	var draw *float64
+	if watts, ok := batteryPower(ctx); ok {
+		parts = append(parts, fmt.Sprintf("%.1f W (battery)", watts))
+		draw = &watts
+	}
+	if runtime.GOOS == "linux" {
+		watts, ok, err := raplPower()
+		switch {
+		case ok:
+			parts = append(parts, fmt.Sprintf("%.1f W (CPU package)", watts))
+			if draw == nil {
+				draw = &watts
+			}
+		case err != nil && len(parts) == 0:
+			errs.record("PowerDraw", err) // energy_uj is root-only since Linux 5.10
+		}
+	}
+	info.Metrics.PowerDraw = draw
+	info.PowerDraw = strings.Join(parts, ", ")
+}
+
+// batteryPower is the discharge rate in watts; on AC the battery reading is
+// the charging rate instead, which says nothing about the system.
+func batteryPower(ctx context.Context) (float64, bool) {
+	switch runtime.GOOS {
+	case "linux":
+		supplies, _ := filepath.Glob("/sys/class/power_supply/*")
+		for _, dir := range supplies {
+			if sysfsString(dir, "type") != "Battery" || sysfsString(dir, "scope") == "Device" || sysfsString(dir, "status") != "Discharging" {
+				continue
+			}
+			if microwatts := sysfsInt(dir, "power_now"); microwatts > 0 {
+				return float64(microwatts) / 1e6, true
+			}
+			// Firmware without power_now: µA × µV
+			if current, voltage := sysfsInt(dir, "current_now"), sysfsInt(dir, "voltage_now"); current > 0 && voltage > 0 {
+				return float64(current) * float64(voltage) / 1e12, true
+			}
+		}
+	case "darwin":
+		objects, _ := ioregObjects(ctx, "AppleSmartBattery")
+		for _, obj := range objects {
+			amperage, ok := obj["InstantAmperage"].(int64) // mA, negative while discharging
+			if !ok {
+				amperage, _ = obj["Amperage"].(int64)
+			}
+			voltage, _ := obj["Voltage"].(int64) // mV
+			if amperage < 0 && voltage > 0 {
+				return float64(-amperage) * float64(voltage) / 1e6, true
+			}
+		}
+	case "windows":
+		var status []struct {
+			Discharging   bool
+			DischargeRate int32 // mW
+		}
+		if err := wmiQuery("SELECT Discharging, DischargeRate FROM BatteryStatus", &status, `root\WMI`); err == nil {
+			for _, s := range status {
+				if s.Discharging && s.DischargeRate > 0 {
+					return float64(s.DischargeRate) / 1000, true
+				}
+			}
+		}
+	}
+	return 0, false
+}
+
+// raplPower samples the package energy counters of the Intel RAPL powercap
+// driver (AMD Zen CPUs use it too), summed over sockets. Subzones such as
+// intel-rapl:0:0 (cores) are part of their package and skipped. ok is false
+// without RAPL, such as on ARM or in a VM.
+func raplPower() (watts float64, ok bool, err error) {
+	zones, _ := filepath.Glob("/sys/class/powercap/intel-rapl:[0-9]*")
+	traceRead("file", "/sys/class/powercap/intel-rapl:*/energy_uj")
+	var packages []string
+	for _, zone := range zones {
+		if strings.Count(filepath.Base(zone), ":") == 1 {
+			packages = append(packages, zone)
+		}
+	}
+	if len(packages) == 0 {
+		return 0, false, nil
+	}
+	read := func() ([]int64, error) {
+		counters := make([]int64, len(packages))
+		for i, zone := range packages {
+			content, err := readFile(filepath.Join(zone, "energy_uj"))
+			if err != nil {
+				return nil, classifyError("RAPL", err)
+			}
+			if counters[i], err = strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64); err != nil {
+				return nil, fmt.Errorf("RAPL: %w", err)
+			}
+		}
+		return counters, nil
+	}
+	before, err := read()
+	if err != nil {
+		return 0, false, err
+	}
+	start := time.Now()
+	time.Sleep(powerSample)
+	after, err := read()
+	if err != nil {
+		return 0, false, err
+	}
+	seconds := time.Since(start).Seconds()
+	var microjoules int64
+	for i, zone := range packages {
+		delta := after[i] - before[i]
+		if delta < 0 { // The counter wrapped at max_energy_range_uj
+			delta += sysfsInt(zone, "max_energy_range_uj")
+		}
+		microjoules += delta
+	}
+	return float64(microjoules) / 1e6 / seconds, true, nil
+}
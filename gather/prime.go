@@ -0,0 +1,97 @@
+package gather
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+)
+
+// hybridTools report the configured switchable graphics mode; the first one
+// installed is asked.
+var hybridTools = []struct {
+	name string
+	args []string
+}{
+	{"prime-select", []string{"query"}},      // Ubuntu nvidia-prime: nvidia, intel or on-demand
+	{"envycontrol", []string{"--query"}},     // hybrid, integrated or nvidia
+	{"supergfxctl", []string{"--get"}},       // ASUS laptops: Hybrid, Integrated, Vfio...
+	{"system76-power", []string{"graphics"}}, // hybrid, integrated, nvidia or compute
+}
+
+// getHybridGraphics describes switchable graphics on laptops with more than
+// one GPU: which one drives the display, the state of the others, which
+// render offloaded work, and the mode set by the PRIME switching tool, e.g.
+// "Intel UHD Graphics 630 (display), NVIDIA GeForce GTX 1650 (offload,
+// suspended), on-demand via prime-select". It is empty with a single GPU.
+func getHybridGraphics(ctx context.Context) (string, error) {
+	gpus := linuxGPUs()
+	if len(gpus) < 2 {
+		return "", nil
+	}
+	names := lookupPCINames(gpus)
+	display := 0 // The boot display, unless another GPU has a monitor attached
+	for i, gpu := range gpus {
+		if hasConnectedOutput(gpu.path) {
+			display = i
+			break
+		}
+	}
+
+	parts := []string{names[display] + " (display)"}
+	for i, gpu := range gpus {
+		if i == display {
+			continue
+		}
+		role := "offload"
+		if status := runtimeStatus(gpu.path); status != "" {
+			role += ", " + status
+		}
+		parts = append(parts, names[i]+" ("+role+")")
+	}
+	if mode, tool := hybridMode(ctx); mode != "" {
+		parts = append(parts, mode+" via "+tool)
+	}
+	return strings.Join(parts, ", "), nil
+}
+
+// hasConnectedOutput reports whether a monitor is plugged into one of the
+// GPU's DRM connectors, e.g. .../drm/card1/card1-eDP-1/status.
+func hasConnectedOutput(pciPath string) bool {
+	statuses, _ := filepath.Glob(filepath.Join(pciPath, "drm", "card*", "card*-*", "status"))
+	for _, status := range statuses {
+		if content, err := readFile(status); err == nil && strings.TrimSpace(string(content)) == "connected" {
+			return true
+		}
+	}
+	return false
+}
+
+// runtimeStatus is the PCI runtime power state, "suspended" while an offload
+// GPU is powered down and "active" while it renders.
+func runtimeStatus(pciPath string) string {
+	content, err := readFile(filepath.Join(pciPath, "power", "runtime_status"))
+	if err != nil {
+		return ""
+	}
+	switch status := strings.TrimSpace(string(content)); status {
+	case "active", "suspended":
+		return status
+	}
+	return ""
+}
+
+func hybridMode(ctx context.Context) (mode, tool string) {
+	for _, t := range hybridTools {
+		if _, err := runner.LookPath(t.name); err != nil {
+			continue
+		}
+		out, err := commandOutput(ctx, t.name, t.args...)
+		if err != nil || out == "" {
+			continue
+		}
+		// supergfxctl and system76-power may add a sentence; the mode is the last word
+		fields := strings.Fields(out)
+		return strings.ToLower(fields[len(fields)-1]), t.name
+	}
+	return "", ""
+}
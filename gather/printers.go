@@ -0,0 +1,49 @@
+package gather
+
+import (
+	"context"
+	"runtime"
+	"strings"
+)
+
+// getPrinters lists the configured printers with the default one marked,
+// e.g. "HP_LaserJet_M404 (default), PDF". It is opt-in (--printers).
+func getPrinters(ctx context.Context) (string, error) {
+	var names []string
+	var defaultName string
+	switch runtime.GOOS {
+	case "windows":
+		var printers []struct {
+			Name    string
+			Default bool
+		}
+		if err := wmiQuery("SELECT Name, Default FROM Win32_Printer", &printers); err != nil {
+			return "", err
+		}
+		for _, p := range printers {
+			names = append(names, p.Name)
+			if p.Default {
+				defaultName = p.Name
+			}
+		}
+	default:
+		// CUPS, on Linux, the BSDs and macOS
+		out, err := commandOutput(ctx, "lpstat", "-e")
+		if err != nil {
+			return "", err
+		}
+		names = strings.Fields(out)
+		// "system default destination: HP_LaserJet_M404", or "no system default destination"
+		if out, err := commandOutput(ctx, "lpstat", "-d"); err == nil {
+			if _, name, ok := strings.Cut(out, "destination: "); ok {
+				defaultName = strings.TrimSpace(name)
+			}
+		}
+	}
+	for i, name := range names {
+		if name == defaultName {
+			names[i] += " (default)"
+		}
+	}
+	return strings.Join(names, ", "), nil
+}
@@ -0,0 +1,90 @@
+package gather
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	topProcesses  = 3
+	processSample = 500 * time.Millisecond // How long CPU use is measured over
+)
+
+// gatherTopProcesses lists the processes using the most CPU over a short
+// sample and the most resident memory, e.g. "firefox 23.1%, code 8.4%,
+// Xorg 3.0%" and "firefox 1.2 GB, code 800.0 MB, Xorg 310.5 MB". CPU
+// percentages are of one core, as in top.
+func gatherTopProcesses(ctx context.Context, info *SystemInfo, errs *errorSet) {
+	before, err := backend.Processes()
+	if err != nil {
+		err = classifyError("processes", err)
+		errs.record("TopCPU", err)
+		errs.record("TopMemory", err)
+		return
+	}
+	start := time.Now()
+	time.Sleep(processSample)
+	after, err := backend.Processes()
+	if err != nil {
+		err = classifyError("processes", err)
+		errs.record("TopCPU", err)
+		errs.record("TopMemory", err)
+		return
+	}
+	seconds := time.Since(start).Seconds()
+
+	cpuBefore := make(map[int32]float64, len(before))
+	for _, p := range before {
+		cpuBefore[p.PID] = p.CPUTime
+	}
+	type usage struct {
+		name string
+		cpu  float64
+		rss  uint64
+	}
+	procs := make([]usage, 0, len(after))
+	for _, p := range after {
+		u := usage{name: p.Name, rss: p.RSS}
+		if prev, ok := cpuBefore[p.PID]; ok && p.CPUTime >= prev {
+			u.cpu = (p.CPUTime - prev) / seconds * 100
+		}
+		procs = append(procs, u)
+	}
+
+	sort.Slice(procs, func(i, j int) bool { return procs[i].cpu > procs[j].cpu })
+	var top []string
+	for _, p := range procs[:min(topProcesses, len(procs))] {
+		top = append(top, fmt.Sprintf("%s %.1f%%", p.name, p.cpu))
+	}
+	info.TopCPU = strings.Join(top, ", ")
+
+	sort.Slice(procs, func(i, j int) bool { return procs[i].rss > procs[j].rss })
+	top = top[:0]
+	for _, p := range procs[:min(topProcesses, len(procs))] {
+		top = append(top, p.name+" "+formatBytes(p.rss))
+	}
+	info.TopMemory = strings.Join(top, ", ")
+}
+
+// runningPrograms lists the program names of the running processes from
+// /proc (Linux). comm is cut to 15 characters, so the name is taken from
+// cmdline instead, which is much cheaper than backend.Processes.
+func runningPrograms() []string {
+	traceRead("file", "/proc/*/cmdline")
+	cmdlines, _ := filepath.Glob("/proc/[0-9]*/cmdline")
+	names := make([]string, 0, len(cmdlines))
+	for _, path := range cmdlines {
+		content, err := os.ReadFile(path)
+		if err != nil || len(content) == 0 {
+			continue // Exited, or a kernel thread
+		}
+		argv0, _, _ := strings.Cut(string(content), "\x00")
+		names = append(names, filepath.Base(argv0))
+	}
+	return names
+}
@@ -0,0 +1,151 @@
+package gather
+
+import (
+	"os"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Source is how a field was obtained in one run (exported for library
+// users); SystemInfo.Sources holds one per field with WithTrace.
+type Source struct {
+	// Reads lists what the gatherer consulted, in order: "file:/etc/os-release",
+	// "command:xrandr", "backend:HostInfo" (gopsutil by default), and on
+	// Windows and macOS "wmi:…", "registry:…" or "sysctl:…". Fields gathered
+	// together (such as OS and Kernel) share their gatherer's list.
+	Reads  []string `json:"reads,omitempty"`
+	Cached bool     `json:"cached,omitempty"` // Served from the on-disk cache, not read this run
+}
+
+// runTrace collects the reads of one traced run by gatherer name.
+type runTrace struct {
+	mu      sync.Mutex
+	current string
+	reads   map[string][]string
+	cached  map[string]bool
+}
+
+// activeTrace is the run being traced, or nil.
+var activeTrace atomic.Pointer[runTrace]
+
+// traceBegin marks name as the gatherer now holding the only job slot.
+func traceBegin(name string) {
+	if t := activeTrace.Load(); t != nil {
+		t.mu.Lock()
+		t.current = name
+		t.mu.Unlock()
+	}
+}
+
+func traceCached(name string) {
+	if t := activeTrace.Load(); t != nil {
+		t.mu.Lock()
+		t.cached[name] = true
+		t.mu.Unlock()
+	}
+}
+
+// traceRead attributes a read, such as ("file", "/proc/modules"), to the
+// running gatherer.
+func traceRead(kind, what string) {
+	t := activeTrace.Load()
+	if t == nil {
+		return
+	}
+	entry := kind + ":" + what
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !slices.Contains(t.reads[t.current], entry) {
+		t.reads[t.current] = append(t.reads[t.current], entry)
+	}
+}
+
+// readFile is os.ReadFile, traced.
+func readFile(path string) ([]byte, error) {
+	traceRead("file", path)
+	return os.ReadFile(path)
+}
+
+// sources turns the trace into per-field Sources, given each gatherer's fields.
+func (t *runTrace) sources(fields map[string][]string) map[string]Source {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	sources := make(map[string]Source)
+	for name, names := range fields {
+		src := Source{Reads: t.reads[name], Cached: t.cached[name]}
+		for _, field := range names {
+			sources[field] = src
+		}
+	}
+	return sources
+}
+
+// tracedBackend records every Backend call before passing it on.
+type tracedBackend struct{ b Backend }
+
+func (t tracedBackend) HostInfo() (*HostStat, error) {
+	traceRead("backend", "HostInfo")
+	return t.b.HostInfo()
+}
+
+func (t tracedBackend) PlatformInformation() (string, string, string, error) {
+	traceRead("backend", "PlatformInformation")
+	return t.b.PlatformInformation()
+}
+
+func (t tracedBackend) Virtualization() (string, string, error) {
+	traceRead("backend", "Virtualization")
+	return t.b.Virtualization()
+}
+
+func (t tracedBackend) CPUInfo() ([]CPUStat, error) {
+	traceRead("backend", "CPUInfo")
+	return t.b.CPUInfo()
+}
+
+func (t tracedBackend) CPUCounts(logical bool) (int, error) {
+	traceRead("backend", "CPUCounts")
+	return t.b.CPUCounts(logical)
+}
+
+func (t tracedBackend) CPUPercent(interval time.Duration) (float64, error) {
+	traceRead("backend", "CPUPercent")
+	return t.b.CPUPercent(interval)
+}
+
+func (t tracedBackend) VirtualMemory() (*MemoryStat, error) {
+	traceRead("backend", "VirtualMemory")
+	return t.b.VirtualMemory()
+}
+
+func (t tracedBackend) SwapMemory() (*MemoryStat, error) {
+	traceRead("backend", "SwapMemory")
+	return t.b.SwapMemory()
+}
+
+func (t tracedBackend) DiskUsage(path string) (*UsageStat, error) {
+	traceRead("backend", "DiskUsage")
+	return t.b.DiskUsage(path)
+}
+
+func (t tracedBackend) Connections(kind string) ([]ConnectionStat, error) {
+	traceRead("backend", "Connections")
+	return t.b.Connections(kind)
+}
+
+func (t tracedBackend) NetIOCounters() ([]NetIOStat, error) {
+	traceRead("backend", "NetIOCounters")
+	return t.b.NetIOCounters()
+}
+
+func (t tracedBackend) Processes() ([]ProcessStat, error) {
+	traceRead("backend", "Processes")
+	return t.b.Processes()
+}
+
+func (t tracedBackend) Temperatures() ([]TemperatureStat, error) {
+	traceRead("backend", "Temperatures")
+	return t.b.Temperatures()
+}
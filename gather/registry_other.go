@@ -0,0 +1,13 @@
+//go:build !windows
+
+package gather
+
+// readRegistry is only available on Windows.
+func readRegistry(path string, names ...string) (map[string]string, error) {
+	return nil, errUnsupported()
+}
+
+// registryValueNames is only available on Windows.
+func registryValueNames(path string) ([]string, error) {
+	return nil, errUnsupported()
+}
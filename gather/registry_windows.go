@@ -0,0 +1,61 @@
+//go:build windows
+
+package gather
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// readRegistry reads the named values under a key such as
+// `HKLM\SOFTWARE\Microsoft\Windows NT\CurrentVersion`. String and DWORD/QWORD
+// values are returned as text; missing values are left out of the map.
+func readRegistry(path string, names ...string) (map[string]string, error) {
+	k, err := openRegistryKey(path)
+	if err != nil {
+		return nil, err
+	}
+	defer k.Close()
+	values := make(map[string]string)
+	for _, name := range names {
+		if s, _, err := k.GetStringValue(name); err == nil {
+			values[name] = s
+		} else if n, _, err := k.GetIntegerValue(name); err == nil {
+			values[name] = strconv.FormatUint(n, 10)
+		}
+	}
+	return values, nil
+}
+
+// registryValueNames lists the names of the values under a key, such as the
+// programs in a Run key.
+func registryValueNames(path string) ([]string, error) {
+	k, err := openRegistryKey(path)
+	if err != nil {
+		return nil, err
+	}
+	defer k.Close()
+	names, err := k.ReadValueNames(0)
+	if err != nil {
+		return nil, classifyError("registry", err)
+	}
+	return names, nil
+}
+
+func openRegistryKey(path string) (registry.Key, error) {
+	traceRead("registry", path)
+	hive, subkey, _ := strings.Cut(path, `\`)
+	roots := map[string]registry.Key{"HKLM": registry.LOCAL_MACHINE, "HKCU": registry.CURRENT_USER}
+	root, ok := roots[hive]
+	if !ok {
+		return 0, errors.New("registry: unknown hive " + hive)
+	}
+	k, err := registry.OpenKey(root, subkey, registry.QUERY_VALUE)
+	if err != nil {
+		return 0, classifyError("registry", err)
+	}
+	return k, nil
+}
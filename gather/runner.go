@@ -0,0 +1,80 @@
+package gather
+
+import (
+	"bytes"
+	"context"
+	"os/exec"
+	"sync"
+)
+
+// Runner starts the external programs gatherers use. The default runs them
+// with os/exec; test doubles returning canned output, or a sandbox that runs
+// programs in a container or refuses them, can be installed with SetRunner.
+// The command policy (SetCommandPolicy) is checked before the Runner is
+// called. Implementations must be safe for concurrent use.
+type Runner interface {
+	// Output runs name with args and returns its standard output, also when
+	// it fails (as with an *exec.ExitError); standard error is discarded. It
+	// should stop the program when ctx is done.
+	Output(ctx context.Context, name string, args ...string) ([]byte, error)
+	// LookPath reports where name would be found, like exec.LookPath.
+	LookPath(name string) (string, error)
+}
+
+type execRunner struct{}
+
+func (r execRunner) Output(ctx context.Context, name string, args ...string) ([]byte, error) {
+	var stdout bytes.Buffer
+	err := r.OutputTo(ctx, &stdout, name, args...)
+	return stdout.Bytes(), err
+}
+
+func (execRunner) OutputTo(ctx context.Context, stdout *bytes.Buffer, name string, args ...string) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdout = stdout
+	cmd.Stderr = nil // Suppress errors
+	return cmd.Run()
+}
+
+func (execRunner) LookPath(name string) (string, error) {
+	return exec.LookPath(name)
+}
+
+// bufferRunner is implemented by Runners that can write a program's output
+// into a buffer the caller owns, which gather reuses across commands.
+type bufferRunner interface {
+	OutputTo(ctx context.Context, stdout *bytes.Buffer, name string, args ...string) error
+}
+
+// maxPooledOutput caps the buffers kept for reuse, so one large journal or
+// dmesg dump doesn't stay allocated for the life of the process.
+const maxPooledOutput = 64 << 10
+
+var outputBuffers = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+// withOutput runs a program through the Runner and passes its output, also
+// when the program failed, to use, which must not keep the slice.
+func withOutput(ctx context.Context, use func(out []byte), name string, args ...string) error {
+	br, ok := runner.(bufferRunner)
+	if !ok {
+		out, err := runner.Output(ctx, name, args...)
+		use(out)
+		return err
+	}
+	buf := outputBuffers.Get().(*bytes.Buffer)
+	buf.Reset()
+	err := br.OutputTo(ctx, buf, name, args...)
+	use(buf.Bytes())
+	if buf.Cap() <= maxPooledOutput {
+		outputBuffers.Put(buf)
+	}
+	return err
+}
+
+var runner Runner = execRunner{}
+
+// SetRunner replaces the way gatherers start external programs. Call it
+// before gathering; it is not synchronized with running collections.
+func SetRunner(r Runner) {
+	runner = r
+}
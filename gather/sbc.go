@@ -0,0 +1,98 @@
+package gather
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ARM single-board computers expose no DMI tables; the board identity lives in
+// the device tree and the Raspberry Pi firmware answers vcgencmd queries.
+
+func getBoardModel(ctx context.Context) (string, error) {
+	content, err := readFile("/proc/device-tree/model")
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil // Not a device-tree system
+	} else if err != nil {
+		return "", classifyError("/proc/device-tree/model", err)
+	}
+	return strings.TrimSpace(strings.TrimRight(string(content), "\x00")), nil
+}
+
+func isDeviceTreeBoard() bool {
+	_, err := os.Stat("/proc/device-tree/model")
+	return err == nil
+}
+
+func gatherSoCTemp(ctx context.Context, info *SystemInfo, errs *errorSet) {
+	if !isDeviceTreeBoard() {
+		return
+	}
+	celsius, err := readSoCTemp(ctx)
+	if err != nil {
+		errs.record("SoCTemp", err)
+		return
+	}
+	info.Metrics.SoCTemp = &celsius
+	info.SoCTemp = formatCelsius(celsius)
+}
+
+func readSoCTemp(ctx context.Context) (float64, error) {
+	// "temp=48.3'C"
+	if out := runCommand(ctx, "vcgencmd", "measure_temp"); strings.HasPrefix(out, "temp=") {
+		value := strings.TrimSuffix(strings.TrimPrefix(out, "temp="), "'C")
+		if temp, err := strconv.ParseFloat(value, 64); err == nil {
+			return temp, nil
+		}
+	}
+	content, err := readFile("/sys/class/thermal/thermal_zone0/temp")
+	if err != nil {
+		return 0, classifyError("thermal_zone0", err)
+	}
+	milli, err := strconv.Atoi(strings.TrimSpace(string(content)))
+	if err != nil {
+		return 0, classifyError("thermal_zone0", err)
+	}
+	return float64(milli) / 1000, nil
+}
+
+// getThrottling decodes the Raspberry Pi firmware's get_throttled bit field.
+func getThrottling(ctx context.Context) (string, error) {
+	if !isDeviceTreeBoard() {
+		return "", nil
+	}
+	out, err := commandOutput(ctx, "vcgencmd", "get_throttled")
+	if err != nil {
+		return "", err
+	}
+	flags, err := strconv.ParseUint(strings.TrimPrefix(out, "throttled="), 0, 32)
+	if err != nil {
+		return "", classifyError("vcgencmd", err)
+	}
+	if flags == 0 {
+		return "None", nil
+	}
+	bits := []struct {
+		mask  uint64
+		label string
+	}{
+		{1 << 0, "under-voltage now"},
+		{1 << 1, "frequency capped now"},
+		{1 << 2, "throttled now"},
+		{1 << 3, "soft temp limit now"},
+		{1 << 16, "under-voltage occurred"},
+		{1 << 17, "frequency capping occurred"},
+		{1 << 18, "throttling occurred"},
+		{1 << 19, "soft temp limit occurred"},
+	}
+	var active []string
+	for _, b := range bits {
+		if flags&b.mask != 0 {
+			active = append(active, b.label)
+		}
+	}
+	return fmt.Sprintf("%s (0x%x)", strings.Join(active, ", "), flags), nil
+}
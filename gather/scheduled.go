@@ -0,0 +1,116 @@
+package gather
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// cronPeriodicDirs hold scripts run-parts starts on a schedule; each counts
+// as one job.
+var cronPeriodicDirs = []string{"/etc/cron.hourly", "/etc/cron.daily", "/etc/cron.weekly", "/etc/cron.monthly"}
+
+// getScheduledJobs counts the configured background jobs, e.g. "7 cron jobs
+// (2 user), 14 systemd timers (1 user)", or on Windows the enabled scheduled
+// tasks, e.g. "142 tasks (9 outside \Microsoft\)". It is opt-in (--scheduled).
+func getScheduledJobs(ctx context.Context) (string, error) {
+	if runtime.GOOS == "windows" {
+		return windowsScheduledTasks()
+	}
+	var parts []string
+	userCron := countCronLines(runCommand(ctx, "crontab", "-l"), false)
+	systemCron := systemCronJobs()
+	if userCron+systemCron > 0 {
+		parts = append(parts, fmt.Sprintf("%d cron jobs (%d user)", userCron+systemCron, userCron))
+	}
+	if runtime.GOOS == "linux" {
+		systemTimers := countLines(runCommand(ctx, "systemctl", "list-timers", "--all", "--no-legend", "--no-pager"))
+		userTimers := countLines(runCommand(ctx, "systemctl", "--user", "list-timers", "--all", "--no-legend", "--no-pager"))
+		if systemTimers+userTimers > 0 {
+			parts = append(parts, fmt.Sprintf("%d systemd timers (%d user)", systemTimers+userTimers, userTimers))
+		}
+	}
+	if len(parts) == 0 {
+		return "None", nil
+	}
+	return strings.Join(parts, ", "), nil
+}
+
+// systemCronJobs counts /etc/crontab, /etc/cron.d and the run-parts scripts.
+func systemCronJobs() int {
+	n := 0
+	crontabs := []string{"/etc/crontab"}
+	traceRead("file", "/etc/cron.d")
+	if entries, err := os.ReadDir("/etc/cron.d"); err == nil {
+		for _, e := range entries {
+			if !e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+				crontabs = append(crontabs, filepath.Join("/etc/cron.d", e.Name()))
+			}
+		}
+	}
+	for _, path := range crontabs {
+		if content, err := readFile(path); err == nil {
+			n += countCronLines(string(content), true)
+		}
+	}
+	for _, dir := range cronPeriodicDirs {
+		traceRead("file", dir)
+		entries, _ := os.ReadDir(dir)
+		for _, e := range entries {
+			if !e.IsDir() && !strings.HasPrefix(e.Name(), ".") && e.Name() != "0anacron" {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// countCronLines counts the job lines of a crontab, skipping comments and
+// variable assignments such as SHELL=/bin/sh. System crontabs have a user
+// column; on Debian /etc/crontab is mostly the run-parts lines for
+// cronPeriodicDirs, which are counted by their scripts instead.
+func countCronLines(crontab string, system bool) int {
+	n := 0
+	for _, line := range strings.Split(crontab, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if strings.Contains(fields[0], "=") {
+			continue
+		}
+		if system && strings.Contains(line, "run-parts") {
+			continue
+		}
+		n++
+	}
+	return n
+}
+
+// windowsScheduledTasks counts the enabled tasks of Task Scheduler (State 1
+// is Disabled); the ones outside \Microsoft\ were added by the user or by
+// installed software.
+func windowsScheduledTasks() (string, error) {
+	var tasks []struct {
+		TaskPath string
+		State    uint32
+	}
+	if err := wmiQuery("SELECT TaskPath, State FROM MSFT_ScheduledTask", &tasks, `root\Microsoft\Windows\TaskScheduler`); err != nil {
+		return "", err
+	}
+	enabled, thirdParty := 0, 0
+	for _, t := range tasks {
+		if t.State == 1 {
+			continue
+		}
+		enabled++
+		if !strings.HasPrefix(t.TaskPath, `\Microsoft\`) {
+			thirdParty++
+		}
+	}
+	return fmt.Sprintf(`%d tasks (%d outside \Microsoft\)`, enabled, thirdParty), nil
+}
@@ -0,0 +1,128 @@
+package gather
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Sensor is one temperature reading (exported for library users and
+// --sensors), in Metrics.Sensors.
+type Sensor struct {
+	Group   string  `json:"group"` // "CPU", "GPU", "Storage", "Ambient", "Network", "Battery" or "Other"
+	Label   string  `json:"label"` // e.g. "Core 0 (coretemp)"
+	Celsius float64 `json:"celsius"`
+}
+
+// sensorGroups maps sensor chip names (the hwmon name on Linux) to a group,
+// in display order; prefixes match chips like "pch_cannonlake".
+var sensorGroups = []struct {
+	group string
+	chips []string
+}{
+	{"CPU", []string{"coretemp", "k10temp", "zenpower", "x86_pkg_temp", "cpu_thermal", "cpu-thermal", "cpu"}},
+	{"GPU", []string{"amdgpu", "radeon", "nouveau", "nvidia", "i915", "xe", "gpu"}},
+	{"Storage", []string{"nvme", "drivetemp", "hdd", "ssd"}},
+	{"Ambient", []string{"acpitz", "pch", "thinkpad", "dell_smm", "asus", "nct", "it87", "ambient", "thermalzone"}},
+	{"Network", []string{"iwlwifi", "ath", "mt7", "r8169", "wifi"}},
+	{"Battery", []string{"bat", "battery"}},
+}
+
+// summarySensors are the labels that stand for the whole chip, listed before
+// its per-core or per-sensor readings.
+var summarySensors = []string{"package", "tctl", "tdie", "composite", "edge"}
+
+// sensorReadings groups and labels the backend's readings.
+func sensorReadings(temps []TemperatureStat) []Sensor {
+	sensors := make([]Sensor, 0, len(temps))
+	for _, t := range temps {
+		chip, label, _ := strings.Cut(t.SensorKey, "_")
+		if label == "" {
+			label = chip
+		} else {
+			label = capitalize(strings.ReplaceAll(label, "_", " ")) + " (" + chip + ")"
+		}
+		sensors = append(sensors, Sensor{Group: sensorGroup(t.SensorKey), Label: label, Celsius: t.Temperature})
+	}
+	return sensors
+}
+
+// sortSensors orders sensors by group, summary sensors first, then label,
+// with numbered labels in numeric order ("Core 2" before "Core 10").
+func sortSensors(sensors []Sensor) {
+	slices.SortStableFunc(sensors, func(a, b Sensor) int {
+		if c := groupRank(a.Group) - groupRank(b.Group); c != 0 {
+			return c
+		}
+		if sa, sb := isSummarySensor(a.Label), isSummarySensor(b.Label); sa != sb {
+			if sa {
+				return -1
+			}
+			return 1
+		}
+		return naturalCompare(a.Label, b.Label)
+	})
+}
+
+func sensorGroup(key string) string {
+	lower := strings.ToLower(key)
+	for _, g := range sensorGroups {
+		for _, chip := range g.chips {
+			if strings.HasPrefix(lower, chip) {
+				return g.group
+			}
+		}
+	}
+	return "Other"
+}
+
+func isSummarySensor(label string) bool {
+	lower := strings.ToLower(label)
+	return slices.ContainsFunc(summarySensors, func(prefix string) bool { return strings.HasPrefix(lower, prefix) })
+}
+
+func groupRank(group string) int {
+	for i, g := range sensorGroups {
+		if g.group == group {
+			return i
+		}
+	}
+	return len(sensorGroups)
+}
+
+func capitalize(s string) string {
+	for i, r := range s {
+		return string(unicode.ToUpper(r)) + s[i+len(string(r)):]
+	}
+	return s
+}
+
+// naturalCompare orders strings with runs of digits compared by value.
+func naturalCompare(a, b string) int {
+	for a != "" && b != "" {
+		da, db := leadingDigits(a), leadingDigits(b)
+		if da != "" && db != "" {
+			na, _ := strconv.Atoi(da)
+			nb, _ := strconv.Atoi(db)
+			if na != nb {
+				return na - nb
+			}
+			a, b = a[len(da):], b[len(db):]
+			continue
+		}
+		if a[0] != b[0] {
+			return int(a[0]) - int(b[0])
+		}
+		a, b = a[1:], b[1:]
+	}
+	return len(a) - len(b)
+}
+
+func leadingDigits(s string) string {
+	end := 0
+	for end < len(s) && s[end] >= '0' && s[end] <= '9' {
+		end++
+	}
+	return s[:end]
+}
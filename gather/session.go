@@ -0,0 +1,42 @@
+package gather
+
+import (
+	"context"
+	"os"
+	"slices"
+	"strings"
+)
+
+// localDisplayFields describe the machine's own screen and desktop, which
+// say nothing useful to someone logged in over SSH; their modules are skipped
+// in SSH sessions unless named in WithModules.
+var localDisplayFields = []string{"DisplayServer", "Resolution", "DE", "WindowManager", "WMPlugins", "DisplayManager", "Portal", "Font", "Wallpaper"}
+
+// sshSession reports whether this runs in an SSH login; sshd sets these in
+// the session environment, OpenSSH for Windows included.
+func sshSession() bool {
+	return os.Getenv("SSH_CONNECTION") != "" || os.Getenv("SSH_TTY") != "" || os.Getenv("SSH_CLIENT") != ""
+}
+
+// getSession reports an SSH session with the client's address, e.g. "SSH
+// (from 10.0.0.5)". SSH_CONNECTION is "client port server port". It is empty
+// for local sessions.
+func getSession(ctx context.Context) (string, error) {
+	if !sshSession() {
+		return "", nil
+	}
+	connection := os.Getenv("SSH_CONNECTION")
+	if connection == "" {
+		connection = os.Getenv("SSH_CLIENT") // Older "client port server_port"
+	}
+	if fields := strings.Fields(connection); len(fields) > 0 {
+		return "SSH (from " + fields[0] + ")", nil
+	}
+	return "SSH", nil
+}
+
+// localDisplayOnly reports whether every field of m is a local display field.
+func localDisplayOnly(m Module) bool {
+	fields := m.Fields()
+	return len(fields) > 0 && !slices.ContainsFunc(fields, func(f string) bool { return !slices.Contains(localDisplayFields, f) })
+}
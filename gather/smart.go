@@ -0,0 +1,78 @@
+package gather
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"slices"
+	"time"
+)
+
+// smartctlTimeout bounds each smartctl call; a drive waking from standby can
+// take seconds to answer.
+const smartctlTimeout = 5 * time.Second
+
+// smartDriveSensors reads drive temperatures with smartctl, for machines
+// whose kernel exposes no drive sensors (SATA disks without the drivetemp
+// module, or other OSes). smartctl needs root (Administrator on Windows), so
+// it is only tried when it can work.
+func smartDriveSensors(ctx context.Context, sensors []Sensor) []Sensor {
+	if slices.ContainsFunc(sensors, func(s Sensor) bool { return s.Group == "Storage" }) {
+		return nil
+	}
+	if runtime.GOOS != "windows" && os.Geteuid() != 0 {
+		return nil
+	}
+	if _, err := runner.LookPath("smartctl"); err != nil {
+		return nil
+	}
+	var scan struct {
+		Devices []struct{ Name, Type string } `json:"devices"`
+	}
+	if smartctlJSON(ctx, &scan, "--scan") != nil {
+		return nil
+	}
+	var found []Sensor
+	for _, dev := range scan.Devices {
+		var report struct {
+			ModelName   string `json:"model_name"`
+			Temperature struct {
+				Current *float64 `json:"current"`
+			} `json:"temperature"`
+		}
+		if smartctlJSON(ctx, &report, "-i", "-A", "-d", dev.Type, dev.Name) != nil || report.Temperature.Current == nil {
+			continue
+		}
+		label := report.ModelName
+		if label == "" {
+			label = dev.Name
+		}
+		found = append(found, Sensor{Group: "Storage", Label: label + " (smartctl)", Celsius: *report.Temperature.Current})
+	}
+	return found
+}
+
+// smartctlJSON runs smartctl with -j and decodes its report into v. The exit
+// status is a bit mask: bits 0 and 1 mean the command failed, the others
+// report disk health, which still comes with a full report.
+func smartctlJSON(ctx context.Context, v any, arg ...string) error {
+	if err := checkCommands("smartctl"); err != nil {
+		return err
+	}
+	traceRead("command", "smartctl")
+	ctx, cancel := context.WithTimeout(ctx, smartctlTimeout)
+	defer cancel()
+	var decodeErr error
+	err := withOutput(ctx, func(out []byte) { decodeErr = json.Unmarshal(out, v) }, "smartctl", append([]string{"-j"}, arg...)...)
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode()&3 == 0 {
+		err = nil
+	}
+	if err != nil {
+		return classifyError("smartctl", err)
+	}
+	return decodeErr
+}
@@ -0,0 +1,49 @@
+package gather
+
+import (
+	"context"
+	"maps"
+	"slices"
+)
+
+// Update is sent by StreamSystemInfo each time a gatherer finishes (exported
+// for library users).
+type Update struct {
+	Fields  []string    // The fields just filled, or failed; see Info.Errors
+	Pending []string    // Fields still being gathered, sorted
+	Info    *SystemInfo // A copy of everything collected so far
+	Done    bool        // Set on the last update, whose Info is the final result
+}
+
+// StreamSystemInfo is GetSystemInfo, but sends an Update as each gatherer
+// finishes instead of blocking until the slowest one does. The channel is
+// closed after the Done update. It is buffered for every update of the run,
+// so a slow reader never holds the gatherers up.
+func StreamSystemInfo(ctx context.Context, opts ...Option) <-chan Update {
+	o := newOptions(opts)
+	modules := selectModules(o)
+	updates := make(chan Update, len(modules)+1)
+	go func() {
+		defer close(updates)
+		info := collect(ctx, o, modules, updates)
+		updates <- Update{Info: info, Done: true}
+	}()
+	return updates
+}
+
+// snapshot copies the results so far for an Update; the caller holds r.mu.
+func (r *gatherRun) snapshot(fields []string) Update {
+	info := *r.info
+	r.errs.mu.Lock()
+	info.Errors = maps.Clone(r.errs.errs)
+	r.errs.mu.Unlock()
+	r.timings.mu.Lock()
+	info.Timings = maps.Clone(r.timings.durations)
+	r.timings.mu.Unlock()
+	var pending []string
+	for _, names := range r.pending {
+		pending = append(pending, names...)
+	}
+	slices.Sort(pending)
+	return Update{Fields: fields, Pending: pending, Info: &info}
+}
@@ -0,0 +1,26 @@
+//go:build darwin
+
+package gather
+
+import "golang.org/x/sys/unix"
+
+// sysctlString reads a string sysctl such as kern.osproductversion without
+// spawning sysctl(8) or sw_vers.
+func sysctlString(name string) (string, error) {
+	traceRead("sysctl", name)
+	s, err := unix.Sysctl(name)
+	if err != nil {
+		return "", classifyError("sysctl "+name, err)
+	}
+	return s, nil
+}
+
+// sysctlUint64 reads a numeric sysctl such as hw.cpufrequency_max.
+func sysctlUint64(name string) (uint64, error) {
+	traceRead("sysctl", name)
+	n, err := unix.SysctlUint64(name)
+	if err != nil {
+		return 0, classifyError("sysctl "+name, err)
+	}
+	return n, nil
+}
@@ -0,0 +1,13 @@
+//go:build !darwin
+
+package gather
+
+// sysctlString is only used on macOS.
+func sysctlString(name string) (string, error) {
+	return "", errUnsupported()
+}
+
+// sysctlUint64 is only used on macOS.
+func sysctlUint64(name string) (uint64, error) {
+	return 0, errUnsupported()
+}
@@ -0,0 +1,62 @@
+package gather
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// taintFlags are the bits of /proc/sys/kernel/tainted with the letter the
+// kernel prints in oops reports, from Documentation/admin-guide/tainted-kernels.rst.
+var taintFlags = []struct {
+	letter string
+	reason string
+}{
+	{"P", "proprietary module"},
+	{"F", "module force loaded"},
+	{"S", "out-of-spec system"},
+	{"R", "module force unloaded"},
+	{"M", "machine check"},
+	{"B", "bad page"},
+	{"U", "user request"},
+	{"D", "oops occurred"},
+	{"A", "ACPI table overridden"},
+	{"W", "warning issued"},
+	{"C", "staging driver"},
+	{"I", "firmware workaround"},
+	{"O", "out-of-tree module"},
+	{"E", "unsigned module"},
+	{"L", "soft lockup"},
+	{"K", "live patched"},
+	{"X", "auxiliary taint"},
+	{"T", "randstruct"},
+	{"N", "test taint"},
+}
+
+// getKernelTaint decodes the kernel taint mask, e.g. "Tainted (PO):
+// proprietary module, out-of-tree module", or "Not tainted".
+func getKernelTaint(ctx context.Context) (string, error) {
+	content, err := readFile("/proc/sys/kernel/tainted")
+	if err != nil {
+		return "", classifyError("/proc/sys/kernel/tainted", err)
+	}
+	mask, err := strconv.ParseUint(strings.TrimSpace(string(content)), 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("/proc/sys/kernel/tainted: %w", err)
+	}
+	if mask == 0 {
+		return "Not tainted", nil
+	}
+	var letters, reasons []string
+	for bit, flag := range taintFlags {
+		if mask&(1<<bit) != 0 {
+			letters = append(letters, flag.letter)
+			reasons = append(reasons, flag.reason)
+		}
+	}
+	if unknown := mask >> len(taintFlags); unknown != 0 {
+		reasons = append(reasons, fmt.Sprintf("unknown bits %#x", unknown<<len(taintFlags)))
+	}
+	return fmt.Sprintf("Tainted (%s): %s", strings.Join(letters, ""), strings.Join(reasons, ", ")), nil
+}
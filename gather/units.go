@@ -0,0 +1,31 @@
+package gather
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// gatherFailedUnits lists the systemd units in the failed state, e.g.
+// "2 (nginx.service, backup.timer)", or "None". Metrics.FailedUnits holds the
+// count for --check 'units>0'.
+func gatherFailedUnits(ctx context.Context, info *SystemInfo, errs *errorSet) {
+	out, err := commandOutput(ctx, "systemctl", "--failed", "--no-legend", "--plain", "--no-pager")
+	if err != nil {
+		errs.record("FailedUnits", err)
+		return
+	}
+	var units []string
+	for _, line := range strings.Split(out, "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			units = append(units, fields[0])
+		}
+	}
+	count := len(units)
+	info.Metrics.FailedUnits = &count
+	if count == 0 {
+		info.FailedUnits = "None"
+		return
+	}
+	info.FailedUnits = fmt.Sprintf("%d (%s)", count, strings.Join(units, ", "))
+}
@@ -0,0 +1,40 @@
+package gather
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGatherFailedUnits(t *testing.T) {
+	tests := []struct {
+		name  string
+		out   string
+		want  string
+		count int
+	}{
+		{"none", "", "None", 0},
+		{"failed", "nginx.service loaded failed failed A high performance web server\n" +
+			"backup.timer  loaded failed failed Nightly backup\n", "2 (nginx.service, backup.timer)", 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useRunner(t, fakeRunner{"systemctl --failed --no-legend --plain --no-pager": tt.out})
+			var info SystemInfo
+			var errs errorSet
+			gatherFailedUnits(context.Background(), &info, &errs)
+			if info.FailedUnits != tt.want || info.Metrics.FailedUnits == nil || *info.Metrics.FailedUnits != tt.count {
+				t.Errorf("got %q (%v), want %q (%d)", info.FailedUnits, info.Metrics.FailedUnits, tt.want, tt.count)
+			}
+		})
+	}
+}
+
+func TestGatherFailedUnitsWithoutSystemd(t *testing.T) {
+	useRunner(t, fakeRunner{})
+	var info SystemInfo
+	var errs errorSet
+	gatherFailedUnits(context.Background(), &info, &errs)
+	if info.Metrics.FailedUnits != nil || errs.errs["FailedUnits"] == nil {
+		t.Errorf("want no count and an error without systemctl, got %v", info.Metrics.FailedUnits)
+	}
+}
@@ -0,0 +1,178 @@
+package gather
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// updateLogTail is how much of the end of a package manager log is read; the
+// latest upgrade is near the end, and the logs grow for years.
+const updateLogTail = 512 << 10
+
+// updateSources find when a package manager last upgraded something; the
+// newest of the ones present wins.
+var updateSources = map[string][]struct {
+	name   string
+	latest func() (time.Time, bool)
+}{
+	"linux": {
+		{"pacman", pacmanLastUpgrade},
+		{"apt", aptLastUpgrade},
+		{"dnf", dnfLastUpgrade},
+		{"zypper", zypperLastInstall},
+	},
+	"darwin":  {{"brew", brewLastInstall}},
+	"windows": {{"Windows Update", windowsLastHotfix}},
+}
+
+// gatherLastUpdate shows when packages were last upgraded, e.g.
+// "2024-05-01 10:22 (3 days ago, pacman)".
+func gatherLastUpdate(ctx context.Context, info *SystemInfo, errs *errorSet) {
+	sources, ok := updateSources[runtime.GOOS]
+	if !ok {
+		errs.record("LastUpdate", errUnsupported())
+		return
+	}
+	var latest time.Time
+	var from string
+	for _, source := range sources {
+		if t, ok := source.latest(); ok && t.After(latest) {
+			latest, from = t, source.name
+		}
+	}
+	if latest.IsZero() {
+		return
+	}
+	info.Metrics.LastUpdate = &latest
+	info.LastUpdate = fmt.Sprintf("%s (%s, %s)", latest.Format("2006-01-02 15:04"), formatAge(time.Since(latest)), from)
+}
+
+// readTail returns up to the last n bytes of a file, starting at a full line.
+func readTail(path string, n int64) (string, error) {
+	traceRead("file", path)
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	offset := max(0, fi.Size()-n)
+	content, err := io.ReadAll(io.NewSectionReader(f, offset, fi.Size()-offset))
+	if err != nil {
+		return "", err
+	}
+	text := string(content)
+	if offset > 0 {
+		if i := strings.IndexByte(text, '\n'); i >= 0 {
+			text = text[i+1:]
+		}
+	}
+	return text, nil
+}
+
+// lastMatch parses the timestamp of the last line matching re, whose first
+// group is the time in one of layouts (local time unless it has a zone).
+func lastMatch(path string, re *regexp.Regexp, layouts ...string) (time.Time, bool) {
+	text, err := readTail(path, updateLogTail)
+	if err != nil {
+		return time.Time{}, false
+	}
+	matches := re.FindAllStringSubmatch(text, -1)
+	for i := len(matches) - 1; i >= 0; i-- {
+		for _, layout := range layouts {
+			if t, err := time.ParseInLocation(layout, matches[i][1], time.Local); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+var (
+	// [2024-05-01T10:22:33+0200] [ALPM] upgraded linux (6.8.8-1 -> 6.8.9-1); older logs use [2019-01-01 10:22]
+	pacmanUpgradeRe = regexp.MustCompile(`(?m)^\[([^\]]+)\] \[ALPM\] upgraded `)
+	// 2024-05-01T10:22:33+0000 SUBDEBUG Upgrade: bash-5.2.26-3.fc40.x86_64 (dnf 4), "Upgraded:" in dnf 5
+	dnfUpgradeRe = regexp.MustCompile(`(?m)^(\S+) \w+ Upgraded?: `)
+	// 2024-05-01 10:22:33|install|bash|5.2.26-1.1|x86_64|root@host|...
+	zypperInstallRe = regexp.MustCompile(`(?m)^([0-9-]+ [0-9:]+)\|install\|`)
+)
+
+func pacmanLastUpgrade() (time.Time, bool) {
+	return lastMatch("/var/log/pacman.log", pacmanUpgradeRe, "2006-01-02T15:04:05-0700", "2006-01-02 15:04")
+}
+
+func dnfLastUpgrade() (time.Time, bool) {
+	return lastMatch("/var/log/dnf.rpm.log", dnfUpgradeRe, "2006-01-02T15:04:05-0700", time.RFC3339)
+}
+
+func zypperLastInstall() (time.Time, bool) {
+	return lastMatch("/var/log/zypp/history", zypperInstallRe, "2006-01-02 15:04:05")
+}
+
+// aptLastUpgrade finds the last history.log transaction with an Upgrade: line.
+// Each transaction is a block:
+//
+//	Start-Date: 2024-05-01  10:22:33
+//	Commandline: apt upgrade
+//	Upgrade: bash:amd64 (5.2.15-2, 5.2.15-2+b1)
+//	End-Date: 2024-05-01  10:22:40
+func aptLastUpgrade() (time.Time, bool) {
+	text, err := readTail("/var/log/apt/history.log", updateLogTail)
+	if err != nil {
+		return time.Time{}, false
+	}
+	var start, latest time.Time
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "Start-Date: "):
+			start, _ = time.ParseInLocation("2006-01-02  15:04:05", strings.TrimPrefix(line, "Start-Date: "), time.Local)
+		case strings.HasPrefix(line, "Upgrade: ") && !start.IsZero():
+			latest = start
+		}
+	}
+	return latest, !latest.IsZero()
+}
+
+// brewLastInstall is the newest version directory in the Cellar, created when
+// a formula is installed or upgraded.
+func brewLastInstall() (time.Time, bool) {
+	var latest time.Time
+	for _, cellar := range []string{"/opt/homebrew/Cellar", "/usr/local/Cellar"} {
+		traceRead("file", filepath.Join(cellar, "*", "*"))
+		versions, _ := filepath.Glob(filepath.Join(cellar, "*", "*"))
+		for _, version := range versions {
+			if fi, err := os.Stat(version); err == nil && fi.ModTime().After(latest) {
+				latest = fi.ModTime()
+			}
+		}
+	}
+	return latest, !latest.IsZero()
+}
+
+// windowsLastHotfix is the newest InstalledOn date of the installed updates.
+func windowsLastHotfix() (time.Time, bool) {
+	var hotfixes []struct{ InstalledOn string }
+	if err := wmiQuery("SELECT InstalledOn FROM Win32_QuickFixEngineering", &hotfixes); err != nil {
+		return time.Time{}, false
+	}
+	var latest time.Time
+	for _, h := range hotfixes {
+		if t, err := time.ParseInLocation("1/2/2006", h.InstalledOn, time.Local); err == nil && t.After(latest) {
+			latest = t
+		}
+	}
+	return latest, !latest.IsZero()
+}
@@ -0,0 +1,168 @@
+package gather
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+)
+
+// usbDevice is one connected USB device; ids are the hex vendor and product.
+type usbDevice struct {
+	name             string
+	vendorID, prodID string
+	speed            string // e.g. "480 Mbps"; empty when unknown
+}
+
+// getUSBDevices lists the connected USB devices like lsusb, one per line,
+// e.g. "Logitech USB Receiver (046d:c52b, 12 Mbps)". Hubs built into the
+// host controller are left out. It is opt-in (--usb).
+func getUSBDevices(ctx context.Context) (string, error) {
+	var devices []usbDevice
+	var err error
+	switch runtime.GOOS {
+	case "linux":
+		devices = linuxUSBDevices()
+	case "darwin":
+		devices, err = darwinUSBDevices(ctx)
+	case "windows":
+		devices, err = windowsUSBDevices()
+	default:
+		return "", errUnsupported()
+	}
+	lines := make([]string, 0, len(devices))
+	for _, d := range devices {
+		details := d.vendorID + ":" + d.prodID
+		if d.speed != "" {
+			details += ", " + d.speed
+		}
+		name := d.name
+		if name == "" {
+			name = "Device"
+		}
+		lines = append(lines, fmt.Sprintf("%s (%s)", name, details))
+	}
+	return strings.Join(lines, "\n"), err
+}
+
+// linuxUSBDevices reads /sys/bus/usb/devices, where devices are named by
+// port path ("1-1.2"), root hubs "usbN", and interfaces "1-1.2:1.0".
+func linuxUSBDevices() []usbDevice {
+	traceRead("file", "/sys/bus/usb/devices")
+	dirs, _ := filepath.Glob("/sys/bus/usb/devices/*")
+	var devices []usbDevice
+	for _, dir := range dirs {
+		base := filepath.Base(dir)
+		if strings.HasPrefix(base, "usb") || strings.Contains(base, ":") {
+			continue
+		}
+		d := usbDevice{vendorID: sysfsString(dir, "idVendor"), prodID: sysfsString(dir, "idProduct")}
+		if d.vendorID == "" {
+			continue
+		}
+		d.name = strings.TrimSpace(sysfsString(dir, "manufacturer") + " " + sysfsString(dir, "product"))
+		if speed := sysfsString(dir, "speed"); speed != "" {
+			d.speed = speed + " Mbps"
+		}
+		devices = append(devices, d)
+	}
+	return devices
+}
+
+// darwinUSBDevices walks the system_profiler USB tree, where devices nest
+// under their bus and hubs in "_items". macOS 14 renamed the report to
+// SPUSBHostDataType and reports ids as plain hex.
+func darwinUSBDevices(ctx context.Context) ([]usbDevice, error) {
+	var devices []usbDevice
+	var lastErr error
+	for _, dataType := range []string{"SPUSBHostDataType", "SPUSBDataType"} {
+		out, err := commandOutput(ctx, "system_profiler", "-json", dataType)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		var report map[string][]darwinUSBItem
+		if err := json.Unmarshal([]byte(out), &report); err != nil {
+			lastErr = fmt.Errorf("system_profiler: %w", err)
+			continue
+		}
+		for _, bus := range report[dataType] {
+			devices = bus.collect(devices)
+		}
+		if len(report[dataType]) > 0 {
+			return devices, nil
+		}
+	}
+	return devices, lastErr
+}
+
+type darwinUSBItem struct {
+	Name         string          `json:"_name"`
+	Items        []darwinUSBItem `json:"_items"`
+	VendorID     string          `json:"vendor_id"`  // "0x046d  (Logitech Inc.)" before macOS 14
+	ProductID    string          `json:"product_id"` // "0xc52b"
+	HostVendorID string          `json:"USBDeviceKeyVendorID"`
+	HostProdID   string          `json:"USBDeviceKeyProductID"`
+	Speed        string          `json:"device_speed"` // "full_speed"
+	LinkSpeed    string          `json:"USBDeviceKeyLinkSpeed"`
+}
+
+func (item darwinUSBItem) collect(devices []usbDevice) []usbDevice {
+	for _, child := range item.Items {
+		vendor, product := firstNonEmpty(child.VendorID, child.HostVendorID), firstNonEmpty(child.ProductID, child.HostProdID)
+		if vendor != "" && product != "" {
+			devices = append(devices, usbDevice{
+				name:     child.Name,
+				vendorID: usbHexID(vendor),
+				prodID:   usbHexID(product),
+				speed:    strings.ReplaceAll(firstNonEmpty(child.Speed, child.LinkSpeed), "_", " "),
+			})
+		}
+		devices = child.collect(devices)
+	}
+	return devices
+}
+
+// usbHexID normalises "0x046d  (Logitech Inc.)" and "0x046D" to "046d".
+func usbHexID(id string) string {
+	fields := strings.Fields(id)
+	if len(fields) == 0 {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(fields[0]), "0x")
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// usbDeviceIDRe matches the ids in a PnP device id such as
+// USB\VID_046D&PID_C52B\6&1A2B3C4D&0&2; interfaces of composite devices add
+// &MI_00 and are skipped.
+var usbDeviceIDRe = regexp.MustCompile(`^USB\\VID_([0-9A-F]{4})&PID_([0-9A-F]{4})\\`)
+
+// windowsUSBDevices lists the USB devices Plug and Play knows, the same
+// SetupAPI data Device Manager shows, through WMI.
+func windowsUSBDevices() ([]usbDevice, error) {
+	var entities []struct{ Name, DeviceID string }
+	if err := wmiQuery(`SELECT Name, DeviceID FROM Win32_PnPEntity WHERE DeviceID LIKE 'USB\\VID[_]%'`, &entities); err != nil {
+		return nil, err
+	}
+	var devices []usbDevice
+	for _, e := range entities {
+		match := usbDeviceIDRe.FindStringSubmatch(strings.ToUpper(e.DeviceID))
+		if match == nil {
+			continue
+		}
+		devices = append(devices, usbDevice{name: e.Name, vendorID: strings.ToLower(match[1]), prodID: strings.ToLower(match[2])})
+	}
+	return devices, nil
+}
@@ -0,0 +1,144 @@
+package gather
+
+import (
+	"bufio"
+	"context"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// getWallpaper reports the file name of the current desktop wallpaper, read
+// from the desktop's settings: gsettings on GNOME-like desktops, the Plasma
+// applets config on KDE, xfconf on Xfce, ~/.fehbg for window managers, System
+// Events on macOS and the Control Panel\Desktop key on Windows.
+func getWallpaper(ctx context.Context) (string, error) {
+	var path string
+	switch runtime.GOOS {
+	case "darwin":
+		path = runCommand(ctx, "osascript", "-e", `tell application "System Events" to get picture of current desktop`)
+	case "windows":
+		path = windowsWallpaper()
+	default:
+		if os.Getenv("WAYLAND_DISPLAY") == "" && os.Getenv("DISPLAY") == "" {
+			return "", nil // No graphical session
+		}
+		path = desktopWallpaper(ctx)
+	}
+	if path == "" {
+		return "", nil
+	}
+	return filepath.Base(path), nil
+}
+
+// desktopWallpaper reads the wallpaper setting of the running desktop,
+// falling back to feh, which most standalone window manager setups use.
+func desktopWallpaper(ctx context.Context) string {
+	desktop := strings.ToLower(os.Getenv("XDG_CURRENT_DESKTOP"))
+	var path string
+	switch {
+	case strings.Contains(desktop, "kde"):
+		path = kdeWallpaper()
+	case strings.Contains(desktop, "xfce"):
+		path = xfceWallpaper(ctx)
+	case strings.Contains(desktop, "cinnamon"):
+		path = gsettingsPath(ctx, "org.cinnamon.desktop.background", "picture-uri")
+	case strings.Contains(desktop, "mate"):
+		path = gsettingsPath(ctx, "org.mate.background", "picture-filename")
+	case strings.Contains(desktop, "gnome"), strings.Contains(desktop, "unity"), strings.Contains(desktop, "budgie"), strings.Contains(desktop, "pantheon"):
+		key := "picture-uri"
+		if strings.Contains(runCommand(ctx, "gsettings", "get", "org.gnome.desktop.interface", "color-scheme"), "dark") {
+			key = "picture-uri-dark"
+		}
+		path = gsettingsPath(ctx, "org.gnome.desktop.background", key)
+	}
+	if path == "" {
+		path = fehWallpaper()
+	}
+	return path
+}
+
+// gsettingsPath reads a key holding a file path or a file:// URI.
+func gsettingsPath(ctx context.Context, schema, key string) string {
+	value := strings.Trim(runCommand(ctx, "gsettings", "get", schema, key), "'")
+	if u, err := url.Parse(value); err == nil && u.Scheme == "file" {
+		return u.Path
+	}
+	return value
+}
+
+// kdeWallpaper reads the Image= line of the first desktop containment's
+// org.kde.image settings, in a section such as
+// [Containments][1][Wallpaper][org.kde.image][General].
+func kdeWallpaper() string {
+	path := filepath.Join(configHome(), "plasma-org.kde.plasma.desktop-appletsrc")
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	traceRead("file", path)
+	inImage := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inImage = strings.HasSuffix(line, "[Wallpaper][org.kde.image][General]")
+			continue
+		}
+		if value, ok := strings.CutPrefix(line, "Image="); ok && inImage {
+			return strings.TrimPrefix(value, "file://")
+		}
+	}
+	return ""
+}
+
+// xfceWallpaper reads the last-image property of the first monitor's first
+// workspace; its name depends on the connector, e.g.
+// /backdrop/screen0/monitoreDP-1/workspace0/last-image.
+func xfceWallpaper(ctx context.Context) string {
+	for _, prop := range strings.Split(runCommand(ctx, "xfconf-query", "-c", "xfce4-desktop", "-l"), "\n") {
+		if strings.HasSuffix(prop, "/workspace0/last-image") {
+			return runCommand(ctx, "xfconf-query", "-c", "xfce4-desktop", "-p", prop)
+		}
+	}
+	return ""
+}
+
+// fehWallpaper reads the image from the command ~/.fehbg replays at login,
+// e.g. feh --no-fehbg --bg-fill '/home/me/walls/forest.jpg'.
+func fehWallpaper() string {
+	home, _ := os.UserHomeDir()
+	data, err := readFile(filepath.Join(home, ".fehbg"))
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if !strings.HasPrefix(strings.TrimSpace(line), "feh ") {
+			continue
+		}
+		if _, rest, ok := strings.Cut(line, "'"); ok {
+			path, _, _ := strings.Cut(rest, "'")
+			return path
+		}
+	}
+	return ""
+}
+
+// windowsWallpaper reads the wallpaper the user picked. The value is empty
+// for slideshows and Spotlight, which leave only the TranscodedWallpaper copy
+// Explorer renders from.
+func windowsWallpaper() string {
+	values, _ := readRegistry(`HKCU\Control Panel\Desktop`, "WallPaper")
+	if path := values["WallPaper"]; path != "" {
+		return path
+	}
+	transcoded := filepath.Join(os.Getenv("APPDATA"), `Microsoft\Windows\Themes\TranscodedWallpaper`)
+	traceRead("file", transcoded)
+	if _, err := os.Stat(transcoded); err == nil {
+		return transcoded
+	}
+	return ""
+}
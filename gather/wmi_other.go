@@ -0,0 +1,8 @@
+//go:build !windows
+
+package gather
+
+// wmiQuery is only available on Windows.
+func wmiQuery(query string, dst any, namespace ...string) error {
+	return errUnsupported()
+}
@@ -0,0 +1,21 @@
+//go:build windows
+
+package gather
+
+import "github.com/yusufpapurcu/wmi"
+
+// wmiQuery runs a WQL query in-process over COM, which costs a few
+// milliseconds where each PowerShell launch costs several hundred. dst must
+// be a pointer to a slice of structs whose fields match the selected
+// properties; an optional namespace overrides the default root\cimv2.
+func wmiQuery(query string, dst any, namespace ...string) error {
+	traceRead("wmi", query)
+	var args []any
+	if len(namespace) > 0 {
+		args = []any{nil, namespace[0]}
+	}
+	if err := wmi.Query(query, dst, args...); err != nil {
+		return classifyError("wmi", err)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package gather
+
+import "time"
+
+// Gatherers run on a pool of worker goroutines that outlive a single
+// collection, so a long-running caller gathering every few seconds reuses
+// them instead of starting one goroutine per module each time. The pool grows
+// to however many modules run at once and shrinks again when workers sit idle.
+
+// workerIdle is how long an idle worker waits for more work before it exits.
+const workerIdle = time.Minute
+
+// gatherTask is one module to run for one collection.
+type gatherTask struct {
+	run    *gatherRun
+	module Module
+	fields []string
+}
+
+// idleTasks hands a task to a worker waiting for one; it is unbuffered, so a
+// send only succeeds when a worker is idle.
+var idleTasks = make(chan gatherTask)
+
+// dispatch runs t on an idle worker, or on a new one if all are busy.
+func dispatch(t gatherTask) {
+	select {
+	case idleTasks <- t:
+	default:
+		go worker(t)
+	}
+}
+
+func worker(t gatherTask) {
+	idle := time.NewTimer(workerIdle)
+	defer idle.Stop()
+	for {
+		t.run.gather(t.module, t.fields)
+		t = gatherTask{} // Don't keep the finished run alive while idle
+		idle.Reset(workerIdle)
+		select {
+		case t = <-idleTasks:
+		case <-idle.C:
+			return
+		}
+	}
+}
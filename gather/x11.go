@@ -0,0 +1,292 @@
+package gather
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Minimal X11 client: just enough of the core protocol to intern atoms and read
+// window properties, so EWMH data can be queried without xprop/wmctrl or cgo.
+
+var x11Order = binary.LittleEndian
+
+type x11Conn struct {
+	conn net.Conn
+	root uint32
+}
+
+// x11Dial connects to the server named by $DISPLAY and completes the setup handshake.
+func x11Dial(display string) (*x11Conn, error) {
+	host, number, err := parseX11Display(display)
+	if err != nil {
+		return nil, err
+	}
+
+	var conn net.Conn
+	if host == "" || host == "unix" {
+		conn, err = net.DialTimeout("unix", "/tmp/.X11-unix/X"+number, time.Second)
+	} else if strings.HasPrefix(host, "/") { // XQuartz launchd socket: /private/tmp/.../org.xquartz:0
+		conn, err = net.DialTimeout("unix", display, time.Second)
+	} else {
+		n, convErr := strconv.Atoi(number)
+		if convErr != nil {
+			return nil, convErr
+		}
+		conn, err = net.DialTimeout("tcp", net.JoinHostPort(host, strconv.Itoa(6000+n)), time.Second)
+	}
+	if err != nil {
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Now().Add(time.Second))
+
+	family, address := x11AuthAddress(conn)
+	authName, authData := x11AuthCookie(family, address, number)
+	req := make([]byte, 12)
+	req[0] = 'l' // Little-endian byte order for everything we send and receive
+	x11Order.PutUint16(req[2:], 11)
+	x11Order.PutUint16(req[6:], uint16(len(authName)))
+	x11Order.PutUint16(req[8:], uint16(len(authData)))
+	req = append(req, x11Pad([]byte(authName))...)
+	req = append(req, x11Pad(authData)...)
+	if _, err := conn.Write(req); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	header := make([]byte, 8)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	setup := make([]byte, int(x11Order.Uint16(header[6:]))*4)
+	if _, err := io.ReadFull(conn, setup); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if header[0] != 1 {
+		conn.Close()
+		return nil, errors.New("x11: connection refused by server")
+	}
+
+	// Skip the fixed setup block, vendor string and pixmap formats to reach the first screen.
+	if len(setup) < 32 {
+		conn.Close()
+		return nil, errors.New("x11: short setup reply")
+	}
+	vendorLen := int(x11Order.Uint16(setup[16:]))
+	formats := int(setup[21])
+	offset := 32 + (vendorLen+3)&^3 + formats*8
+	if len(setup) < offset+4 {
+		conn.Close()
+		return nil, errors.New("x11: no screens")
+	}
+	return &x11Conn{conn: conn, root: x11Order.Uint32(setup[offset:])}, nil
+}
+
+func (x *x11Conn) Close() error {
+	return x.conn.Close()
+}
+
+// request sends a single request and reads its reply, returning the bytes after the 32-byte header.
+func (x *x11Conn) request(req []byte) ([]byte, []byte, error) {
+	if _, err := x.conn.Write(req); err != nil {
+		return nil, nil, err
+	}
+	for {
+		header := make([]byte, 32)
+		if _, err := io.ReadFull(x.conn, header); err != nil {
+			return nil, nil, err
+		}
+		switch header[0] {
+		case 0:
+			return nil, nil, fmt.Errorf("x11: request failed with error code %d", header[1])
+		case 1:
+			body := make([]byte, int(x11Order.Uint32(header[4:]))*4)
+			if _, err := io.ReadFull(x.conn, body); err != nil {
+				return nil, nil, err
+			}
+			return header, body, nil
+		}
+		// Anything else is an event; we select none, but skip them defensively.
+	}
+}
+
+// internAtom looks up an existing atom by name (InternAtom with only-if-exists).
+func (x *x11Conn) internAtom(name string) (uint32, error) {
+	req := make([]byte, 8)
+	req[0] = 16
+	req[1] = 1
+	x11Order.PutUint16(req[2:], uint16(2+(len(name)+3)/4))
+	x11Order.PutUint16(req[4:], uint16(len(name)))
+	req = append(req, x11Pad([]byte(name))...)
+	header, _, err := x.request(req)
+	if err != nil {
+		return 0, err
+	}
+	atom := x11Order.Uint32(header[8:])
+	if atom == 0 {
+		return 0, fmt.Errorf("x11: atom %s does not exist", name)
+	}
+	return atom, nil
+}
+
+// getProperty reads up to 4 KiB of a window property of any type.
+func (x *x11Conn) getProperty(window, property uint32) ([]byte, error) {
+	req := make([]byte, 24)
+	req[0] = 20
+	x11Order.PutUint16(req[2:], 6)
+	x11Order.PutUint32(req[4:], window)
+	x11Order.PutUint32(req[8:], property)
+	x11Order.PutUint32(req[20:], 1024)
+	header, body, err := x.request(req)
+	if err != nil {
+		return nil, err
+	}
+	format := int(header[1])
+	n := int(x11Order.Uint32(header[16:])) * format / 8
+	if format == 0 || n > len(body) {
+		return nil, errors.New("x11: property not set")
+	}
+	return body[:n], nil
+}
+
+// getWindowProperty resolves the atom name and reads the property from window.
+func (x *x11Conn) getWindowProperty(window uint32, name string) ([]byte, error) {
+	atom, err := x.internAtom(name)
+	if err != nil {
+		return nil, err
+	}
+	return x.getProperty(window, atom)
+}
+
+// getEWMHWindowManager follows _NET_SUPPORTING_WM_CHECK to the WM's check window
+// and returns its _NET_WM_NAME, which every EWMH-compliant WM (bspwm, awesome,
+// openbox, i3, herbstluftwm, ...) sets regardless of how the session was started.
+func getEWMHWindowManager() string {
+	display := os.Getenv("DISPLAY")
+	if display == "" {
+		return ""
+	}
+	x, err := x11Dial(display)
+	if err != nil {
+		return ""
+	}
+	defer x.Close()
+
+	check, err := x.getWindowProperty(x.root, "_NET_SUPPORTING_WM_CHECK")
+	if err != nil || len(check) < 4 {
+		return ""
+	}
+	wmWindow := x11Order.Uint32(check)
+
+	name, err := x.getWindowProperty(wmWindow, "_NET_WM_NAME")
+	if err != nil || len(name) == 0 {
+		name, err = x.getWindowProperty(wmWindow, "WM_NAME")
+		if err != nil {
+			return ""
+		}
+	}
+	return strings.TrimSpace(string(bytes.TrimRight(name, "\x00")))
+}
+
+// parseX11Display splits "host:display.screen" into host and display number.
+func parseX11Display(display string) (string, string, error) {
+	i := strings.LastIndex(display, ":")
+	if i < 0 {
+		return "", "", fmt.Errorf("x11: invalid display %q", display)
+	}
+	number := display[i+1:]
+	if dot := strings.Index(number, "."); dot >= 0 {
+		number = number[:dot]
+	}
+	if number == "" {
+		return "", "", fmt.Errorf("x11: invalid display %q", display)
+	}
+	return display[:i], number, nil
+}
+
+// Xauthority address families (Xauth.h).
+const (
+	x11FamilyInternet  = 0
+	x11FamilyInternet6 = 6
+	x11FamilyLocal     = 256 // Address is the hostname
+	x11FamilyWild      = 65535
+)
+
+// x11AuthAddress is the Xauthority family and address of the connection, as
+// libxcb derives them: the hostname for local sockets and loopback, otherwise
+// the server's IP address.
+func x11AuthAddress(conn net.Conn) (uint16, []byte) {
+	if addr, ok := conn.RemoteAddr().(*net.TCPAddr); ok && !addr.IP.IsLoopback() {
+		if ip4 := addr.IP.To4(); ip4 != nil {
+			return x11FamilyInternet, ip4
+		}
+		return x11FamilyInternet6, addr.IP.To16()
+	}
+	hostname, _ := os.Hostname()
+	return x11FamilyLocal, []byte(hostname)
+}
+
+// x11AuthCookie finds the MIT-MAGIC-COOKIE-1 for the display in the Xauthority file.
+func x11AuthCookie(family uint16, address []byte, number string) (string, []byte) {
+	path := os.Getenv("XAUTHORITY")
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", nil
+		}
+		path = filepath.Join(home, ".Xauthority")
+	}
+	content, err := readFile(path)
+	if err != nil {
+		return "", nil
+	}
+	return parseXauthority(content, family, address, number)
+}
+
+// parseXauthority picks the first MIT-MAGIC-COOKIE-1 entry for the address
+// and display, like libXau's XauGetBestAuthByAddr: FamilyWild entries match
+// any address, and entries without a display number match every display.
+// Entries for other hosts, such as ones added by ssh -X, are skipped.
+func parseXauthority(content []byte, family uint16, address []byte, number string) (string, []byte) {
+	r := bytes.NewReader(content)
+	readField := func() ([]byte, error) {
+		var n uint16
+		if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+			return nil, err
+		}
+		field := make([]byte, n)
+		_, err := io.ReadFull(r, field)
+		return field, err
+	}
+	for {
+		var entryFamily uint16
+		if err := binary.Read(r, binary.BigEndian, &entryFamily); err != nil {
+			return "", nil
+		}
+		entryAddress, err1 := readField()
+		num, err2 := readField()
+		name, err3 := readField()
+		data, err4 := readField()
+		if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
+			return "", nil
+		}
+		addressMatches := entryFamily == x11FamilyWild || (entryFamily == family && bytes.Equal(entryAddress, address))
+		if addressMatches && string(name) == "MIT-MAGIC-COOKIE-1" && (len(num) == 0 || string(num) == number) {
+			return string(name), data
+		}
+	}
+}
+
+func x11Pad(b []byte) []byte {
+	return append(b, make([]byte, (4-len(b)%4)%4)...)
+}
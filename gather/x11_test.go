@@ -0,0 +1,46 @@
+package gather
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// xauthEntry encodes one Xauthority record.
+func xauthEntry(family uint16, address, number, name, data string) []byte {
+	var b bytes.Buffer
+	_ = binary.Write(&b, binary.BigEndian, family)
+	for _, field := range []string{address, number, name, data} {
+		_ = binary.Write(&b, binary.BigEndian, uint16(len(field)))
+		b.WriteString(field)
+	}
+	return b.Bytes()
+}
+
+func TestParseXauthority(t *testing.T) {
+	forwarded := xauthEntry(x11FamilyLocal, "otherhost", "0", "MIT-MAGIC-COOKIE-1", "remote")
+	local := xauthEntry(x11FamilyLocal, "myhost", "0", "MIT-MAGIC-COOKIE-1", "local")
+	tests := []struct {
+		name    string
+		content []byte
+		family  uint16
+		address string
+		number  string
+		want    string
+	}{
+		{"skips other hosts", append(forwarded, local...), x11FamilyLocal, "myhost", "0", "local"},
+		{"other display", local, x11FamilyLocal, "myhost", "1", ""},
+		{"wild", xauthEntry(x11FamilyWild, "", "", "MIT-MAGIC-COOKIE-1", "wild"), x11FamilyLocal, "myhost", "1", "wild"},
+		{"other protocol", xauthEntry(x11FamilyLocal, "myhost", "0", "XDM-AUTHORIZATION-1", "xdm"), x11FamilyLocal, "myhost", "0", ""},
+		{"tcp", append(local, xauthEntry(x11FamilyInternet, "\xc0\x00\x02\x07", "0", "MIT-MAGIC-COOKIE-1", "tcp")...), x11FamilyInternet, "\xc0\x00\x02\x07", "0", "tcp"},
+		{"truncated", local[:10], x11FamilyLocal, "myhost", "0", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, data := parseXauthority(tt.content, tt.family, []byte(tt.address), tt.number)
+			if string(data) != tt.want {
+				t.Errorf("cookie = %q, want %q", data, tt.want)
+			}
+		})
+	}
+}
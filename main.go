@@ -1,35 +1,288 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"log"
 	"os"
+	"strings"
+	"text/template"
+	"time"
 
 	// Import local packages using the module path defined in go.mod
+	"KernelView-Go/config"
 	"KernelView-Go/display"
 	"KernelView-Go/gather"
+	"KernelView-Go/snapshot"
 )
 
 func main() {
 	// Define flags with shortcuts and detailed usage messages
 	var fastFlag bool
-	flag.BoolVar(&fastFlag, "fast", false, "Run in fast mode: Skips slower checks like CPU usage, packages, languages, temperature, network speed, and open ports for quicker results.")
+	flag.BoolVar(&fastFlag, "fast", false, "Run in fast mode: skips every field that samples over time, scans the system or starts slow external programs (CPU usage, bandwidth, sensors, packages, drives, open ports and connections, fonts, wallpaper, ...) for quicker results.")
 	flag.BoolVar(&fastFlag, "f", false, "Run in fast mode (shorthand).")
+	var verboseFlag bool
+	flag.BoolVar(&verboseFlag, "verbose", false, "Show additional detail fields such as loaded kernel modules.")
+	flag.BoolVar(&verboseFlag, "v", false, "Show additional detail fields (shorthand).")
+	var versionFlag bool
+	flag.BoolVar(&versionFlag, "version", false, "Print the version, commit, build date and Go version, then exit.")
+	var showErrorsFlag bool
+	flag.BoolVar(&showErrorsFlag, "show-errors", false, "Show fields that could not be gathered with the reason (e.g. \"Graphics API: error (glxinfo: tool not found)\") instead of hiding them.")
+	var clearFlag bool
+	flag.BoolVar(&clearFlag, "clear", false, "Clear the screen before printing the info (only when output is a terminal).")
+	var timingsFlag bool
+	flag.BoolVar(&timingsFlag, "timings", false, "After the output, print how long each gatherer took (to stderr), slowest first.")
+	var runSlowFlag bool
+	flag.BoolVar(&runSlowFlag, "run-slow", false, "Run fields that are normally skipped for having been slow in their last three runs on this machine (see cache.slow_budget in the config file).")
+	var sensorsFlag bool
+	flag.BoolVar(&sensorsFlag, "sensors", false, "After the output, list every temperature sensor with its label, grouped by CPU, GPU, storage and so on. Not with --fast, which skips temperatures.")
+	var sourcesFlag bool
+	flag.BoolVar(&sourcesFlag, "sources", false, "After the output, print how each field was obtained (files, commands, backend calls or the cache) to stderr. Gatherers run one at a time, so this is slower.")
+	var updatesFlag bool
+	flag.BoolVar(&updatesFlag, "updates", false, "Also count upgradable packages (apt, checkupdates, dnf, zypper, brew, winget). Slow: the checks can take tens of seconds.")
+	var journalFlag bool
+	flag.BoolVar(&journalFlag, "journal-errors", false, "Also count error and critical messages logged since boot (journal or dmesg, Linux).")
+	var usbFlag bool
+	flag.BoolVar(&usbFlag, "usb", false, "Also list the connected USB devices with their vendor:product ids.")
+	var printersFlag bool
+	flag.BoolVar(&printersFlag, "printers", false, "Also list the configured printers and the default one (CUPS or Windows).")
+	var scheduledFlag bool
+	flag.BoolVar(&scheduledFlag, "scheduled", false, "Also count cron jobs and systemd timers (scheduled tasks on Windows).")
+	var portsFlag string
+	flag.StringVar(&portsFlag, "ports", "", "Use \"all\" to list every listening TCP and UDP socket with its address instead of the first five TCP ports.")
+	var noCacheFlag bool
+	flag.BoolVar(&noCacheFlag, "no-cache", false, "Ignore cached results for slow fields (packages, GPU, languages) and gather them afresh.")
+	var jobsFlag int
+	flag.IntVar(&jobsFlag, "jobs", 0, "Run at most N gatherers (and their external commands) at once; 0 means no limit. Useful on low-core devices.")
+	flag.IntVar(&jobsFlag, "j", 0, "Limit concurrent gatherers (shorthand).")
+	var timeoutFlag time.Duration
+	flag.DurationVar(&timeoutFlag, "timeout", 0, "Return within this long (e.g. 2s) with whatever was gathered, marking the rest as skipped; for shell prompts and MOTD scripts. 0 waits for everything.")
+	var checkFlag string
+	flag.StringVar(&checkFlag, "check", "", "Health probe: check thresholds such as 'disk>90,ram>95,swap>50,cpu>90,temp>=80' instead of printing the info; exits 1 if any is violated, 3 if a metric is unavailable.")
+	var snapshotPath string
+	flag.StringVar(&snapshotPath, "snapshot", "", "Also save the gathered data to FILE (JSON) for a later --diff.")
+	var diffPath string
+	flag.StringVar(&diffPath, "diff", "", "Instead of the info, show which fields changed since the snapshot in FILE (kernel, RAM size, open ports, ...).")
+	var historyPath string
+	flag.StringVar(&historyPath, "history", "", "Append this run's data as a timestamped JSON line to FILE (overrides the config file's history setting).")
+	var historyOf string
+	flag.StringVar(&historyOf, "history-of", "", "Print when FIELD (a SystemInfo field such as Kernel or GPU) changed, according to the history file, then exit.")
+	var logTarget string
+	flag.StringVar(&logTarget, "log", "", "Also send --check results to \"syslog\" or \"journal\" (systemd), with error/warning/info priorities.")
+	var langFlag string
+	flag.StringVar(&langFlag, "lang", "", "Language for labels and group names: "+strings.Join(display.Languages(), ", ")+" (default: from LANG).")
+	var configPath string
+	flag.StringVar(&configPath, "config", "", "Path to the JSON config file (default: "+config.DefaultPath()+").")
+	var logoFlag string
+	flag.StringVar(&logoFlag, "logo", "", "Draw an image logo beside the info: \"distro\", a path to a PNG/JPEG/GIF, or \"none\". Needs a Kitty, iTerm2 or Sixel capable terminal; overrides the config file.")
+	var styleFlag string
+	flag.StringVar(&styleFlag, "style", "", "Layout style: "+strings.Join(display.LayoutStyles(), ", ")+" (default: classic); overrides the config file.")
+	var themeFlag string
+	flag.StringVar(&themeFlag, "theme", "", "Colour theme: "+strings.Join(display.ThemeNames(), ", ")+"; overrides the config file.")
+	var accentFlag string
+	flag.StringVar(&accentFlag, "accent", "", "Colour of the title and group rules: a hex colour, \"random\" (a new hue every run) or \"distro\" (os-release ANSI_COLOR); overrides the config file.")
+	var listThemesFlag bool
+	flag.BoolVar(&listThemesFlag, "list-themes", false, "Print a sample of every colour theme, then exit.")
+	var iconsFlag string
+	flag.StringVar(&iconsFlag, "icons", "", "Prefix each key with an icon: nerd (needs a Nerd Font), emoji or none; overrides the config file.")
+	var onelineFlag bool
+	flag.BoolVar(&onelineFlag, "oneline", false, "Print a one-line summary such as 'Arch Linux | 6.9.3 | Core i7-1165G7 | 62% RAM | 41% disk | up 3d 4h' for status bars and prompts; the fields are set in the config file.")
+	var greetFlag bool
+	flag.BoolVar(&greetFlag, "greet", false, "Print a two-line greeting for shell rc files and MOTDs: fast mode, no external commands and a 50ms time limit (unless --timeout is set).")
+	var outputFormat string
+	flag.StringVar(&outputFormat, "output", "text", "Output format: text, html (a styled page), html-fragment (just the styled block, for embedding), csv or tsv (host,category,key,value rows).")
+	var formatFlag string
+	flag.StringVar(&formatFlag, "format", "", "Print a Go text/template over the SystemInfo fields instead, e.g. '{{.OS}} | {{.Kernel}} | {{.RAM}}'. Overrides --output.")
+	var exportPath string
+	flag.StringVar(&exportPath, "export", "", "Also render the themed output (with the logo, if any) to an image file; the format follows the extension (.png or .svg).")
+	var screenshotFlag bool
+	flag.BoolVar(&screenshotFlag, "screenshot", false, "After rendering, capture the terminal window via xdg-desktop-portal and save it to the current directory (desktop Linux only).")
 
 	// Custom usage message for --help / -h
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage of %s:\n", os.Args[0])
-		fmt.Fprintf(os.Stderr, "  %s [flags]\n\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s [flags]\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s completion bash|zsh|fish|powershell\n\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "Flags:\n")
 		flag.PrintDefaults()
 		fmt.Fprintf(os.Stderr, "\nDescription:\n")
 		fmt.Fprintf(os.Stderr, "  KernelView Go displays system information.\n")
 		fmt.Fprintf(os.Stderr, "  Default mode performs a comprehensive scan (slower).\n")
 		fmt.Fprintf(os.Stderr, "  Fast mode (-f, --fast) provides essential info instantly by skipping slower checks.\n")
+		fmt.Fprintf(os.Stderr, "  Verbose mode (-v, --verbose) adds detail fields for debugging.\n")
+	}
+
+	// "completion <shell>" is the only subcommand; everything else is flags
+	if len(os.Args) > 1 && os.Args[1] == "completion" {
+		if len(os.Args) != 3 {
+			fmt.Fprintf(os.Stderr, "Usage: %s completion bash|zsh|fish|powershell\n", os.Args[0])
+			os.Exit(2)
+		}
+		if err := writeCompletion(os.Stdout, os.Args[2]); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		return
 	}
 
 	flag.Parse()
 
+	if versionFlag {
+		writeVersion(os.Stdout)
+		return
+	}
+
+	log.SetFlags(0)
+	log.SetPrefix("kernelview: ")
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	if greetFlag {
+		// Every new shell pays for this, so skip anything that can be slow
+		fastFlag, verboseFlag = true, false
+		cfg.Commands.None = true
+		if timeoutFlag == 0 {
+			timeoutFlag = 50 * time.Millisecond
+		}
+	}
+	gather.SetCommandPolicy(cfg.Commands)
+	gatherOpts := []gather.Option{
+		gather.WithCache(cfg.CacheTTL(), noCacheFlag),
+		gather.WithJobs(jobsFlag),
+		gather.WithTimeout(timeoutFlag),
+	}
+	if fastFlag {
+		gatherOpts = append(gatherOpts, gather.WithFast())
+	}
+	if greetFlag {
+		// Only what WriteGreeting shows; these change too often to cache
+		gatherOpts = append(gatherOpts, gather.WithModules("Hostname", "OS", "Kernel", "Uptime", "RAM", "Disk", "IPAddress"))
+	}
+	if verboseFlag {
+		gatherOpts = append(gatherOpts, gather.WithVerbose())
+	}
+	if !runSlowFlag && checkFlag == "" { // Health checks must not skip their metrics
+		gatherOpts = append(gatherOpts, gather.WithSlowBudget(cfg.SlowBudget()))
+	}
+	if sourcesFlag {
+		gatherOpts = append(gatherOpts, gather.WithTrace())
+	}
+	if updatesFlag {
+		gatherOpts = append(gatherOpts, gather.WithOptIn("PendingUpdates"))
+	}
+	if journalFlag {
+		gatherOpts = append(gatherOpts, gather.WithOptIn("JournalErrors"))
+	}
+	if usbFlag {
+		gatherOpts = append(gatherOpts, gather.WithOptIn("USBDevices"))
+	}
+	if printersFlag {
+		gatherOpts = append(gatherOpts, gather.WithOptIn("Printers"))
+	}
+	if scheduledFlag {
+		gatherOpts = append(gatherOpts, gather.WithOptIn("ScheduledJobs"))
+	}
+	switch portsFlag {
+	case "":
+	case "all":
+		gatherOpts = append(gatherOpts, gather.WithAllPorts())
+	default:
+		fmt.Fprintf(os.Stderr, "unknown --ports value %q (use all)\n", portsFlag)
+		os.Exit(2)
+	}
+
+	// Parse the thresholds and template before gathering so typos fail fast
+	var checks []threshold
+	if checkFlag != "" {
+		checks, err = parseChecks(checkFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	}
+	if historyPath == "" {
+		historyPath = cfg.History
+	}
+	if historyOf != "" {
+		if historyPath == "" {
+			fmt.Fprintln(os.Stderr, "history: no history file (set --history or \"history\" in the config file)")
+			os.Exit(2)
+		}
+		history, err := snapshot.ReadHistory(historyPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		for _, s := range snapshot.FieldChanges(history, historyOf) {
+			fmt.Printf("%s  %s\n", s.Taken.Local().Format("2006-01-02 15:04"), s.Fields[historyOf])
+		}
+		return
+	}
+
+	var baseline *snapshot.Snapshot
+	if diffPath != "" {
+		baseline, err = snapshot.Load(diffPath)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+	logger, err := openAlertLog(logTarget)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	var format *template.Template
+	if formatFlag != "" {
+		format, err = template.New("format").Parse(formatFlag)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	}
+	switch outputFormat {
+	case "text", "html", "html-fragment", "csv", "tsv":
+	default:
+		fmt.Fprintf(os.Stderr, "unknown output format %q (use text, html, html-fragment, csv or tsv)\n", outputFormat)
+		os.Exit(2)
+	}
+	if logoFlag != "" {
+		cfg.Logo.Source = logoFlag
+	}
+	display.SetLogo(cfg.Logo)
+	if styleFlag != "" {
+		cfg.Style = styleFlag
+	}
+	if err := display.SetStyle(cfg.Style); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if iconsFlag != "" {
+		cfg.Icons.Style = iconsFlag
+	}
+	if err := display.SetIcons(cfg.Icons); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := display.SetThresholds(cfg.Thresholds); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if err := display.SetGroups(cfg.Groups); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	display.SetTitle(cfg.Title)
+	display.SetShowErrors(showErrorsFlag)
+	display.SetClear(clearFlag)
+	if err := display.SetLanguage(langFlag); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
 	// Select theme based on flag
 	var currentTheme display.Theme
 	if fastFlag {
@@ -37,10 +290,130 @@ func main() {
 	} else {
 		currentTheme = display.NormalTheme // Use exported theme
 	}
+	if listThemesFlag {
+		if err := display.ListThemes(os.Stdout, currentTheme); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if themeFlag != "" {
+		cfg.Theme = themeFlag
+	}
+	currentTheme, err = currentTheme.Named(cfg.Theme)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	if accentFlag != "" {
+		cfg.Colors.Accent = accentFlag
+	}
+	currentTheme, err = currentTheme.WithColors(cfg.Colors)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
 
-	// Call the gather package's function
-	info := gather.GetSystemInfo(fastFlag)
+	// Call the gather package's function. The plain text view fills in as
+	// fields arrive; everything else needs the complete result.
+	start := time.Now()
+	var info *gather.SystemInfo
+	streamed := checks == nil && baseline == nil && !greetFlag && !onelineFlag && format == nil && outputFormat == "text"
+	if streamed {
+		info = display.DisplayStream(gather.StreamSystemInfo(context.Background(), gatherOpts...), currentTheme)
+	} else {
+		info = gather.GetSystemInfo(context.Background(), gatherOpts...)
+	}
+	elapsed := time.Since(start)
 
 	// Call the display package's function
-	display.DisplaySystemInfo(info, currentTheme)
+	switch {
+	case checks != nil:
+		code, problems := runChecks(checks, info, logger)
+		if logger != nil {
+			logger.Close()
+		}
+		if err := sendWebhook(cfg.Webhook, info.Hostname, problems); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(code)
+	case baseline != nil:
+		display.WriteDiff(os.Stdout, baseline, baseline.Diff(info), currentTheme)
+	case greetFlag:
+		if err := display.WriteGreeting(os.Stdout, info, currentTheme); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	case onelineFlag:
+		if err := display.WriteOneLine(os.Stdout, info, cfg.OneLine); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+	case format != nil:
+		var out strings.Builder
+		if err := format.Execute(&out, info); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Println(strings.TrimSuffix(out.String(), "\n"))
+	case streamed:
+		// Already drawn by DisplayStream
+	case outputFormat == "html", outputFormat == "html-fragment":
+		if err := display.WriteHTML(os.Stdout, info, currentTheme, outputFormat == "html-fragment"); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	case outputFormat == "csv", outputFormat == "tsv":
+		separator := ','
+		if outputFormat == "tsv" {
+			separator = '\t'
+		}
+		if err := display.WriteCSV(os.Stdout, info, separator); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	if snapshotPath != "" {
+		if err := snapshot.Save(snapshotPath, info); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	if historyPath != "" {
+		if err := snapshot.Append(historyPath, info); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+
+	if sensorsFlag {
+		display.WriteSensors(os.Stdout, info)
+	}
+
+	if timingsFlag {
+		display.WriteTimings(os.Stderr, info, elapsed)
+	}
+
+	if sourcesFlag {
+		display.WriteSources(os.Stderr, info)
+	}
+
+	if exportPath != "" {
+		if err := display.ExportImage(info, currentTheme, exportPath); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Exported to %s\n", exportPath)
+	}
+
+	if screenshotFlag {
+		path, err := display.CaptureScreenshot()
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Screenshot saved to %s\n", path)
+	}
 }
@@ -0,0 +1,72 @@
+package snapshot
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"KernelView-Go/gather"
+)
+
+// maxHistoryLine bounds one JSON line; a snapshot is a few KiB even with --verbose.
+const maxHistoryLine = 1 << 20
+
+// Append adds a snapshot of info as one JSON line to the history file at path,
+// creating it if needed.
+func Append(path string, info *gather.SystemInfo) error {
+	line, err := json.Marshal(New(info))
+	if err != nil {
+		return fmt.Errorf("history: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("history: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return fmt.Errorf("history: %w", err)
+	}
+	return f.Close()
+}
+
+// ReadHistory loads every snapshot in the history file, oldest first.
+// Malformed lines (e.g. from an interrupted write) are skipped.
+func ReadHistory(path string) ([]*Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+	defer f.Close()
+	var snaps []*Snapshot
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), maxHistoryLine)
+	for scanner.Scan() {
+		s := &Snapshot{}
+		if json.Unmarshal(scanner.Bytes(), s) == nil && s.Fields != nil {
+			snaps = append(snaps, s)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("history: %w", err)
+	}
+	return snaps, nil
+}
+
+// FieldChanges returns the snapshots at which field took a new value: the
+// first recorded value, then every change after it.
+func FieldChanges(history []*Snapshot, field string) []*Snapshot {
+	var changes []*Snapshot
+	last, seen := "", false
+	for _, s := range history {
+		v, ok := s.Fields[field]
+		if !ok {
+			continue // Not gathered in that run (e.g. --fast), not a change
+		}
+		if !seen || v != last {
+			changes = append(changes, s)
+		}
+		last, seen = v, true
+	}
+	return changes
+}
@@ -0,0 +1,119 @@
+// Package snapshot saves gathered system info to a file and compares the
+// current state against it, for before/after upgrade audits.
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"time"
+
+	"KernelView-Go/gather"
+)
+
+// Snapshot is the on-disk format (exported).
+type Snapshot struct {
+	Taken    time.Time         `json:"taken"`
+	Fields   map[string]string `json:"fields"`             // SystemInfo field name -> value; empty fields omitted
+	Gathered []string          `json:"gathered,omitempty"` // Fields the run tried to gather; missing in older snapshots
+}
+
+// Change is one field that differs between a snapshot and the current state.
+// Old or New is empty when the field appeared or disappeared.
+type Change struct {
+	Field    string
+	Old, New string
+}
+
+// volatileFields change from run to run on their own, so diffs ignore them.
+var volatileFields = map[string]bool{
+	"Uptime": true, "CPUUsage": true, "CPUSpeed": true, "Temperature": true, "SoCTemp": true, "Throttling": true,
+	"NetTraffic": true, "Bandwidth": true, "Connections": true, "TopCPU": true, "TopMemory": true, "LastUpdate": true, "PendingUpdates": true, "Battery": true, "PowerDraw": true, "JournalErrors": true,
+}
+
+// usageTotalRe picks the capacity out of "used / total (percent)" values, so
+// RAM, Swap and Disk only show up in a diff when their size changes.
+var usageTotalRe = regexp.MustCompile(`/\s*([0-9.]+\s*[KMGT]?B)`)
+
+// New captures the non-empty fields of info.
+func New(info *gather.SystemInfo) *Snapshot {
+	s := &Snapshot{Taken: time.Now().UTC(), Fields: make(map[string]string), Gathered: info.Gathered}
+	for _, name := range gather.FieldNames() {
+		if v := info.Result(name).Value; v != "" {
+			s.Fields[name] = v
+		}
+	}
+	return s
+}
+
+// Save writes a snapshot of info to path as indented JSON.
+func Save(path string, info *gather.SystemInfo) error {
+	content, err := json.MarshalIndent(New(info), "", "  ")
+	if err != nil {
+		return fmt.Errorf("snapshot: %w", err)
+	}
+	if err := os.WriteFile(path, append(content, '\n'), 0o644); err != nil {
+		return fmt.Errorf("snapshot: %w", err)
+	}
+	return nil
+}
+
+// Load reads a snapshot written by Save.
+func Load(path string) (*Snapshot, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot: %w", err)
+	}
+	s := &Snapshot{}
+	if err := json.Unmarshal(content, s); err != nil {
+		return nil, fmt.Errorf("snapshot: %s: %w", path, err)
+	}
+	return s, nil
+}
+
+// Diff lists the fields that differ between the snapshot and info, in
+// SystemInfo order, skipping values that fluctuate on their own and fields
+// that only one of the two runs gathered (e.g. one used --fast).
+func (s *Snapshot) Diff(info *gather.SystemInfo) []Change {
+	current := New(info)
+	before, after := gatheredSet(s.Gathered), gatheredSet(info.Gathered)
+	var changes []Change
+	for _, name := range gather.FieldNames() {
+		if volatileFields[name] || !before(name) || !after(name) {
+			continue
+		}
+		old, now := s.Fields[name], current.Fields[name]
+		if comparable(name, old) == comparable(name, now) {
+			continue
+		}
+		if now == "" && info.Errors[name] != nil {
+			continue // A failed read, not a removal
+		}
+		changes = append(changes, Change{Field: name, Old: old, New: now})
+	}
+	return changes
+}
+
+// gatheredSet reports whether a run gathered a field; without a list (older
+// snapshots) every field counts as gathered.
+func gatheredSet(fields []string) func(string) bool {
+	if fields == nil {
+		return func(string) bool { return true }
+	}
+	set := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		set[f] = true
+	}
+	return func(field string) bool { return set[field] }
+}
+
+func comparable(field, value string) string {
+	switch field {
+	case "RAM", "Swap", "Disk":
+		if m := usageTotalRe.FindStringSubmatch(value); m != nil {
+			return m[1]
+		}
+	}
+	return value
+}
@@ -0,0 +1,50 @@
+package snapshot
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"KernelView-Go/gather"
+)
+
+func TestDiff(t *testing.T) {
+	full := &Snapshot{
+		Fields:   map[string]string{"Kernel": "6.1.0-17-amd64", "GPU": "AMD Radeon RX 6800", "Packages": "APT (2101)", "RAM": "3.1GB / 15.5GB (20%)", "Uptime": "3 hours"},
+		Gathered: []string{"Kernel", "GPU", "Packages", "RAM", "Uptime", "USBDevices"},
+	}
+	tests := []struct {
+		name string
+		snap *Snapshot
+		info *gather.SystemInfo
+		want []Change
+	}{
+		{"kernel upgraded", full,
+			&gather.SystemInfo{Kernel: "6.1.0-18-amd64", GPU: "AMD Radeon RX 6800", Packages: "APT (2101)", RAM: "5.0GB / 15.5GB (32%)", Uptime: "1 minute",
+				Gathered: []string{"Kernel", "GPU", "Packages", "RAM", "Uptime"}},
+			[]Change{{Field: "Kernel", Old: "6.1.0-17-amd64", New: "6.1.0-18-amd64"}}},
+		{"fast run skips unselected fields", full,
+			&gather.SystemInfo{Kernel: "6.1.0-17-amd64", RAM: "3.1GB / 15.5GB (20%)", Gathered: []string{"Kernel", "RAM"}},
+			nil},
+		{"field only gathered now", &Snapshot{Fields: map[string]string{"Kernel": "6.1"}, Gathered: []string{"Kernel"}},
+			&gather.SystemInfo{Kernel: "6.1", USBDevices: "Logitech Receiver", Gathered: []string{"Kernel", "USBDevices"}},
+			nil},
+		{"failed read is not a removal", full,
+			&gather.SystemInfo{Kernel: "6.1.0-17-amd64", Packages: "APT (2101)", RAM: "3.1GB / 15.5GB (20%)", Gathered: []string{"Kernel", "GPU", "Packages", "RAM"},
+				Errors: map[string]error{"GPU": errors.New("lspci: tool missing")}},
+			nil},
+		{"removal", full,
+			&gather.SystemInfo{Kernel: "6.1.0-17-amd64", Packages: "APT (2101)", RAM: "3.1GB / 15.5GB (20%)", Gathered: []string{"Kernel", "GPU", "Packages", "RAM"}},
+			[]Change{{Field: "GPU", Old: "AMD Radeon RX 6800"}}},
+		{"older snapshot without a gathered list", &Snapshot{Fields: map[string]string{"Kernel": "6.1", "GPU": "AMD Radeon RX 6800"}},
+			&gather.SystemInfo{Kernel: "6.2", Gathered: []string{"Kernel"}},
+			[]Change{{Field: "Kernel", Old: "6.1", New: "6.2"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.snap.Diff(tt.info); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"KernelView-Go/config"
+)
+
+// webhookTimeout keeps a slow endpoint from stalling cron jobs.
+const webhookTimeout = 10 * time.Second
+
+// sendWebhook POSTs the --check problems to the configured webhook, unless one
+// was already sent to the same URL within the configured interval.
+func sendWebhook(cfg config.WebhookConfig, host string, problems []string) error {
+	if cfg.URL == "" || len(problems) == 0 {
+		return nil
+	}
+	statePath := webhookStatePath()
+	state := map[string]time.Time{}
+	if content, err := os.ReadFile(statePath); err == nil {
+		_ = json.Unmarshal(content, &state)
+	}
+	if last, ok := state[cfg.URL]; ok && time.Since(last) < cfg.Interval() {
+		return nil // Rate limited
+	}
+
+	text := fmt.Sprintf("kernelview on %s: %s", host, strings.Join(problems, "; "))
+	var payload interface{}
+	switch cfg.Format {
+	case "slack":
+		payload = map[string]string{"text": text}
+	case "discord":
+		payload = map[string]string{"content": text}
+	default:
+		payload = map[string]interface{}{
+			"host":     host,
+			"time":     time.Now().UTC().Format(time.RFC3339),
+			"problems": problems,
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	client := &http.Client{Timeout: webhookTimeout}
+	resp, err := client.Post(cfg.URL, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook: %s returned %s", cfg.URL, resp.Status)
+	}
+
+	state[cfg.URL] = time.Now()
+	if content, err := json.Marshal(state); err == nil && statePath != "" {
+		_ = os.MkdirAll(filepath.Dir(statePath), 0o755)
+		_ = os.WriteFile(statePath, content, 0o644)
+	}
+	return nil
+}
+
+func webhookStatePath() string {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "kernelview", "webhook.json")
+}